@@ -1,6 +1,7 @@
 package main
 
 import (
+	"log"
 	"net/http"
 
 	"github.com/go-chi/chi"
@@ -8,18 +9,29 @@ import (
 	"github.com/himtar/go-boilerplate/libraries/server"
 )
 
-func app() *chi.Mux {
-	server := chi.NewRouter()
+func app() (*chi.Mux, error) {
+	mux := chi.NewRouter()
 
-	server.Get("/", func(w http.ResponseWriter, r *http.Request) {
+	// no logger of its own, requests carry the one BuildAndStartServer sets up
+	router, err := server.NewHTTPRouter(mux, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	router.Get("/", func(w http.ResponseWriter, r *http.Request) {
 		w.Write([]byte("Hello, World!"))
 	})
 
-	server.Mount("/auth", handlers.AuthHandler())
+	router.Mount("/auth", handlers.AuthHandler())
 
-	return server
+	return mux, nil
 }
 
 func main() {
-	server.BuildAndStartServer(app())
+	mux, err := app()
+	if err != nil {
+		log.Fatalf("Error building routes: %v", err)
+	}
+
+	server.BuildAndStartServer(mux)
 }
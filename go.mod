@@ -2,6 +2,13 @@ module github.com/himtar/go-boilerplate
 
 go 1.21.5
 
-require github.com/go-chi/chi v1.5.5
+require (
+	github.com/fsnotify/fsnotify v1.7.0
+	github.com/go-chi/chi v1.5.5
+	github.com/joho/godotenv v1.5.1
+	github.com/santhosh-tekuri/jsonschema/v5 v5.3.1
+	golang.org/x/net v0.21.0
+	golang.org/x/sync v0.10.0
+)
 
-require github.com/joho/godotenv v1.5.1 // indirect
+require golang.org/x/sys v0.17.0 // indirect
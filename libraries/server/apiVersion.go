@@ -0,0 +1,57 @@
+package server
+
+import (
+	"context"
+	"net/http"
+	"strings"
+
+	"github.com/himtar/go-boilerplate/pkg/response"
+)
+
+// APIVersionHeader is the default header APIVersionMiddleware reads the version from.
+const APIVersionHeader = "X-API-Version"
+
+const apiVersionKey contextKey = "api_version"
+
+// APIVersionMiddleware resolves the API version a request asks for through
+// headerName (APIVersionHeader when empty) and stores it in the context, see
+// APIVersion. supported is ordered oldest to newest, requests without the
+// header get the newest one and unsupported versions are rejected with a 400.
+func APIVersionMiddleware(supported []string, headerName string) func(http.Handler) http.Handler {
+	if headerName == "" {
+		headerName = APIVersionHeader
+	}
+
+	allowed := make(map[string]bool, len(supported))
+	for _, version := range supported {
+		allowed[version] = true
+	}
+
+	latest := ""
+	if len(supported) > 0 {
+		latest = supported[len(supported)-1]
+	}
+
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Add("Vary", headerName)
+
+			version := strings.TrimSpace(r.Header.Get(headerName))
+			if version == "" {
+				version = latest
+			} else if !allowed[version] {
+				response.SendBadRequest(w, "Unsupported API version "+version+", supported: "+strings.Join(supported, ", "))
+				return
+			}
+
+			ctx := context.WithValue(r.Context(), apiVersionKey, version)
+			next.ServeHTTP(w, r.WithContext(ctx))
+		})
+	}
+}
+
+// APIVersion returns the version resolved by APIVersionMiddleware, "" outside of it.
+func APIVersion(ctx context.Context) string {
+	version, _ := ctx.Value(apiVersionKey).(string)
+	return version
+}
@@ -0,0 +1,57 @@
+package server
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestAPIVersionMiddleware(t *testing.T) {
+	tests := []struct {
+		name        string
+		headerName  string
+		header      string
+		version     string
+		wantStatus  int
+		wantVersion string
+	}{
+		{name: "supported", header: APIVersionHeader, version: "2024-01-01", wantStatus: http.StatusOK, wantVersion: "2024-01-01"},
+		{name: "absent defaults to latest", wantStatus: http.StatusOK, wantVersion: "2025-06-01"},
+		{name: "unsupported", header: APIVersionHeader, version: "2023-01-01", wantStatus: http.StatusBadRequest},
+		{name: "custom header", headerName: "Api-Version", header: "Api-Version", version: "2024-01-01", wantStatus: http.StatusOK, wantVersion: "2024-01-01"},
+		{name: "default header ignored with custom name", headerName: "Api-Version", header: APIVersionHeader, version: "2023-01-01", wantStatus: http.StatusOK, wantVersion: "2025-06-01"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got string
+			handler := APIVersionMiddleware([]string{"2024-01-01", "2025-06-01"}, tt.headerName)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				got = APIVersion(r.Context())
+			}))
+
+			req := httptest.NewRequest(http.MethodGet, "/orders", nil)
+			if tt.header != "" {
+				req.Header.Set(tt.header, tt.version)
+			}
+			rec := httptest.NewRecorder()
+			handler.ServeHTTP(rec, req)
+
+			if rec.Code != tt.wantStatus {
+				t.Fatalf("status = %d, want %d", rec.Code, tt.wantStatus)
+			}
+			if got != tt.wantVersion {
+				t.Errorf("APIVersion() = %q, want %q", got, tt.wantVersion)
+			}
+			if rec.Header().Get("Vary") == "" {
+				t.Error("Vary header not set")
+			}
+		})
+	}
+}
+
+func TestAPIVersionOutsideMiddleware(t *testing.T) {
+	if got := APIVersion(context.Background()); got != "" {
+		t.Errorf("APIVersion() = %q, want empty", got)
+	}
+}
@@ -0,0 +1,25 @@
+package server
+
+import (
+	"net/http"
+
+	"github.com/himtar/go-boilerplate/pkg/logger"
+)
+
+// AuditContextMiddleware stores the client IP, user agent, request and trace
+// IDs in the context as a logger.AuditContext, so audit entries logged with
+// the request context carry the same metadata everywhere. Handlers can read
+// it with logger.AuditContextFrom. Mount it after the request ID and RealIP
+// middleware.
+func AuditContextMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ctx := logger.WithAuditContext(r.Context(), logger.AuditContext{
+			ClientIP:  clientIP(r),
+			UserAgent: r.UserAgent(),
+			RequestID: RequestID(r.Context()),
+			TraceID:   TraceID(r.Context()),
+		})
+
+		next.ServeHTTP(w, r.WithContext(ctx))
+	})
+}
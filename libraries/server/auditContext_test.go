@@ -0,0 +1,63 @@
+package server
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/go-chi/chi"
+	"github.com/himtar/go-boilerplate/pkg/logger"
+)
+
+func TestAuditContextMiddleware(t *testing.T) {
+	tests := []struct {
+		name       string
+		remoteAddr string
+		userAgent  string
+		traceID    string
+		want       logger.AuditContext
+	}{
+		{
+			name:       "request metadata",
+			remoteAddr: "203.0.113.7:52100",
+			userAgent:  "curl/8.4.0",
+			traceID:    "trace-1",
+			want:       logger.AuditContext{ClientIP: "203.0.113.7", UserAgent: "curl/8.4.0", RequestID: "req-1", TraceID: "trace-1"},
+		},
+		{
+			name:       "generated trace ID, no user agent",
+			remoteAddr: "198.51.100.2:4000",
+			want:       logger.AuditContext{ClientIP: "198.51.100.2", RequestID: "req-1", TraceID: "gen-1"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got logger.AuditContext
+			var ok bool
+
+			app := chi.NewRouter()
+			app.Use(NewRequestIDMiddleware(sequence("req")))
+			app.Use(NewTraceIDMiddleware(sequence("gen")))
+			app.Use(AuditContextMiddleware)
+			app.Post("/login", func(w http.ResponseWriter, r *http.Request) {
+				got, ok = logger.AuditContextFrom(r.Context())
+			})
+
+			req := httptest.NewRequest(http.MethodPost, "/login", nil)
+			req.RemoteAddr = tt.remoteAddr
+			req.Header.Set("User-Agent", tt.userAgent)
+			if tt.traceID != "" {
+				req.Header.Set(TraceIDHeader, tt.traceID)
+			}
+			app.ServeHTTP(httptest.NewRecorder(), req)
+
+			if !ok {
+				t.Fatal("no audit context in the request context")
+			}
+			if got != tt.want {
+				t.Errorf("audit context = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
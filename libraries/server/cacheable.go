@@ -0,0 +1,77 @@
+package server
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/himtar/go-boilerplate/pkg/response"
+)
+
+// cachingHeaders are stripped from responses to unsafe methods.
+var cachingHeaders = []string{"Cache-Control", "Expires", "ETag", "Last-Modified"}
+
+// cacheWriter applies the caching policy once the status is known.
+type cacheWriter struct {
+	http.ResponseWriter
+	maxAge      time.Duration
+	safe        bool
+	wroteHeader bool
+}
+
+func (c *cacheWriter) WriteHeader(status int) {
+	if c.wroteHeader {
+		return
+	}
+	c.wroteHeader = true
+
+	header := c.Header()
+	switch {
+	case !c.safe:
+		for _, name := range cachingHeaders {
+			header.Del(name)
+		}
+	case status >= 200 && status < 300 && header.Get("Cache-Control") == "":
+		// a handler's own Cache-Control wins
+		response.WithCacheControl(c.ResponseWriter, c.maxAge, true)
+		header.Add("Vary", "Accept, Accept-Encoding")
+	}
+
+	c.ResponseWriter.WriteHeader(status)
+}
+
+func (c *cacheWriter) Write(b []byte) (int, error) {
+	if !c.wroteHeader {
+		c.WriteHeader(http.StatusOK)
+	}
+	return c.ResponseWriter.Write(b)
+}
+
+func (c *cacheWriter) Flush() {
+	_ = c.FlushError()
+}
+
+// FlushError flushes like Flush but reports failures, see http.ResponseController.
+func (c *cacheWriter) FlushError() error {
+	if !c.wroteHeader {
+		c.WriteHeader(http.StatusOK)
+	}
+	return http.NewResponseController(c.ResponseWriter).Flush()
+}
+
+// Unwrap lets http.ResponseController reach the underlying writer.
+func (c *cacheWriter) Unwrap() http.ResponseWriter {
+	return c.ResponseWriter
+}
+
+// CacheableMiddleware standardizes caching for shared caches: successful GET
+// and HEAD responses are marked public for maxAge and vary on Accept and
+// Accept-Encoding, unless the handler set its own Cache-Control. Responses to
+// any other method get their caching headers stripped.
+func CacheableMiddleware(maxAge time.Duration) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			safe := r.Method == http.MethodGet || r.Method == http.MethodHead
+			next.ServeHTTP(&cacheWriter{ResponseWriter: w, maxAge: maxAge, safe: safe}, r)
+		})
+	}
+}
@@ -0,0 +1,67 @@
+package server
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestCacheableMiddleware(t *testing.T) {
+	tests := []struct {
+		name             string
+		method           string
+		status           int
+		handlerCache     string
+		wantCacheControl string
+		wantVary         bool
+	}{
+		{name: "GET 200", method: http.MethodGet, status: http.StatusOK, wantCacheControl: "public, max-age=60", wantVary: true},
+		{name: "HEAD 200", method: http.MethodHead, status: http.StatusOK, wantCacheControl: "public, max-age=60", wantVary: true},
+		{name: "GET 404", method: http.MethodGet, status: http.StatusNotFound},
+		{name: "GET with own Cache-Control", method: http.MethodGet, status: http.StatusOK, handlerCache: "no-cache", wantCacheControl: "no-cache"},
+		{name: "POST", method: http.MethodPost, status: http.StatusCreated, handlerCache: "public, max-age=600"},
+		{name: "DELETE", method: http.MethodDelete, status: http.StatusNoContent},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			handler := CacheableMiddleware(time.Minute)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if tt.handlerCache != "" {
+					w.Header().Set("Cache-Control", tt.handlerCache)
+					w.Header().Set("ETag", `"v1"`)
+				}
+				w.WriteHeader(tt.status)
+			}))
+
+			rec := httptest.NewRecorder()
+			handler.ServeHTTP(rec, httptest.NewRequest(tt.method, "/items", nil))
+
+			if rec.Code != tt.status {
+				t.Errorf("status = %d, want %d", rec.Code, tt.status)
+			}
+			if got := rec.Header().Get("Cache-Control"); got != tt.wantCacheControl {
+				t.Errorf("Cache-Control = %q, want %q", got, tt.wantCacheControl)
+			}
+			if got := rec.Header().Get("Vary") != ""; got != tt.wantVary {
+				t.Errorf("Vary set = %v, want %v", got, tt.wantVary)
+			}
+			if tt.method == http.MethodPost && rec.Header().Get("ETag") != "" {
+				t.Error("ETag not stripped from a POST response")
+			}
+		})
+	}
+}
+
+func TestCacheableMiddlewareImplicitStatus(t *testing.T) {
+	handler := CacheableMiddleware(time.Minute)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("items"))
+	}))
+
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/items", nil))
+
+	if got := rec.Header().Get("Cache-Control"); got != "public, max-age=60" {
+		t.Errorf("Cache-Control = %q, want the caching policy on an implicit 200", got)
+	}
+}
@@ -0,0 +1,157 @@
+package server
+
+import (
+	"compress/gzip"
+	"log/slog"
+	"net/http"
+	"strings"
+)
+
+// Compression levels accepted by CompressMiddleware.
+const (
+	CompressDefault         = gzip.DefaultCompression
+	CompressBestSpeed       = gzip.BestSpeed
+	CompressBestCompression = gzip.BestCompression
+)
+
+// clampCompressLevel keeps level within the gzip range, warning when it had
+// to adjust it. Anything below it means no preference and gets the default.
+func clampCompressLevel(level int) int {
+	clamped := level
+	switch {
+	case level < CompressDefault:
+		clamped = CompressDefault
+	case level > CompressBestCompression:
+		clamped = CompressBestCompression
+	default:
+		return level
+	}
+
+	slog.Warn("compression level out of range, clamped",
+		slog.Int("configured", level),
+		slog.Int("used", clamped),
+	)
+	return clamped
+}
+
+// NoCompressHeader is a response hint telling CompressMiddleware to leave the body as is.
+// It is stripped before the response goes out.
+const NoCompressHeader = "X-No-Compress"
+
+// DisableCompression marks the response as not to be compressed, e.g. for live streams.
+func DisableCompression(w http.ResponseWriter) {
+	w.Header().Set(NoCompressHeader, "1")
+}
+
+// compressWriter decides on the first write whether the response gets gzipped.
+type compressWriter struct {
+	http.ResponseWriter
+	level       int
+	gz          *gzip.Writer
+	decided     bool
+	wroteHeader bool
+}
+
+func (c *compressWriter) decide(status int) {
+	if c.decided {
+		return
+	}
+	c.decided = true
+
+	header := c.Header()
+	skip := status < http.StatusOK ||
+		status == http.StatusNoContent ||
+		status == http.StatusNotModified ||
+		header.Get(NoCompressHeader) != "" ||
+		header.Get("Content-Encoding") != "" ||
+		strings.HasPrefix(header.Get("Content-Type"), "text/event-stream")
+	header.Del(NoCompressHeader)
+
+	if skip {
+		return
+	}
+
+	gz, err := gzip.NewWriterLevel(c.ResponseWriter, c.level)
+	if err != nil {
+		return
+	}
+
+	header.Set("Content-Encoding", "gzip")
+	header.Del("Content-Length")
+	c.gz = gz
+}
+
+func (c *compressWriter) WriteHeader(status int) {
+	if c.wroteHeader {
+		return
+	}
+	c.wroteHeader = true
+
+	c.decide(status)
+	c.ResponseWriter.WriteHeader(status)
+}
+
+func (c *compressWriter) Write(b []byte) (int, error) {
+	if !c.wroteHeader {
+		if c.Header().Get("Content-Type") == "" {
+			c.Header().Set("Content-Type", http.DetectContentType(b))
+		}
+		c.WriteHeader(http.StatusOK)
+	}
+
+	if c.gz != nil {
+		return c.gz.Write(b)
+	}
+
+	return c.ResponseWriter.Write(b)
+}
+
+func (c *compressWriter) Flush() {
+	_ = c.FlushError()
+}
+
+// FlushError flushes like Flush but reports failures, see http.ResponseController.
+func (c *compressWriter) FlushError() error {
+	if c.gz != nil {
+		if err := c.gz.Flush(); err != nil {
+			return err
+		}
+	}
+
+	return http.NewResponseController(c.ResponseWriter).Flush()
+}
+
+// Unwrap lets http.ResponseController and the trace ID lookup reach the underlying writer.
+func (c *compressWriter) Unwrap() http.ResponseWriter {
+	return c.ResponseWriter
+}
+
+func (c *compressWriter) close() {
+	if c.gz != nil {
+		c.gz.Close()
+	}
+}
+
+// CompressMiddleware gzips responses for clients accepting it. Server-sent
+// event streams and responses carrying the NoCompressHeader hint are passed
+// through untouched so live streams aren't buffered. level goes from
+// CompressDefault (-1) to CompressBestCompression (9), other values are clamped.
+func CompressMiddleware(level int) func(http.Handler) http.Handler {
+	level = clampCompressLevel(level)
+
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Add("Vary", "Accept-Encoding")
+
+			if r.Method == http.MethodHead || !strings.Contains(r.Header.Get("Accept-Encoding"), "gzip") {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			cw := &compressWriter{ResponseWriter: w, level: level}
+			defer cw.close()
+
+			next.ServeHTTP(cw, r)
+		})
+	}
+}
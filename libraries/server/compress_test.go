@@ -0,0 +1,146 @@
+package server
+
+import (
+	"bytes"
+	"compress/gzip"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestCompressMiddleware(t *testing.T) {
+	const body = `{"items":[1,2,3]}`
+
+	tests := []struct {
+		name           string
+		contentType    string
+		noCompress     bool
+		acceptEncoding string
+		wantGzip       bool
+	}{
+		{name: "JSON is compressed", contentType: "application/json", acceptEncoding: "gzip", wantGzip: true},
+		{name: "SSE is not compressed", contentType: "text/event-stream", acceptEncoding: "gzip"},
+		{name: "no-compress hint", contentType: "application/json", noCompress: true, acceptEncoding: "gzip"},
+		{name: "client without gzip", contentType: "application/json"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			handler := CompressMiddleware(CompressDefault)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.Header().Set("Content-Type", tt.contentType)
+				if tt.noCompress {
+					DisableCompression(w)
+				}
+				w.Write([]byte(body))
+			}))
+
+			req := httptest.NewRequest(http.MethodGet, "/", nil)
+			if tt.acceptEncoding != "" {
+				req.Header.Set("Accept-Encoding", tt.acceptEncoding)
+			}
+			rec := httptest.NewRecorder()
+			handler.ServeHTTP(rec, req)
+
+			gzipped := rec.Header().Get("Content-Encoding") == "gzip"
+			if gzipped != tt.wantGzip {
+				t.Fatalf("gzipped = %v, want %v", gzipped, tt.wantGzip)
+			}
+			if rec.Header().Get(NoCompressHeader) != "" {
+				t.Error("no-compress hint leaked into the response")
+			}
+
+			var reader io.Reader = rec.Body
+			if gzipped {
+				gz, err := gzip.NewReader(rec.Body)
+				if err != nil {
+					t.Fatal(err)
+				}
+				reader = gz
+			}
+
+			got, err := io.ReadAll(reader)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if string(got) != body {
+				t.Errorf("body = %q, want %q", got, body)
+			}
+		})
+	}
+}
+
+func TestCompressLevelConstants(t *testing.T) {
+	tests := []struct {
+		name  string
+		level int
+		want  int
+	}{
+		{name: "default", level: CompressDefault, want: gzip.DefaultCompression},
+		{name: "best speed", level: CompressBestSpeed, want: gzip.BestSpeed},
+		{name: "best compression", level: CompressBestCompression, want: gzip.BestCompression},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if tt.level != tt.want {
+				t.Errorf("constant = %d, want gzip level %d", tt.level, tt.want)
+			}
+		})
+	}
+}
+
+func TestClampCompressLevel(t *testing.T) {
+	tests := []struct {
+		name     string
+		level    int
+		want     int
+		wantWarn bool
+	}{
+		{name: "default kept", level: CompressDefault, want: CompressDefault},
+		{name: "no compression kept", level: gzip.NoCompression, want: gzip.NoCompression},
+		{name: "in range kept", level: 5, want: 5},
+		{name: "best compression kept", level: CompressBestCompression, want: CompressBestCompression},
+		{name: "too high clamped", level: 42, want: CompressBestCompression, wantWarn: true},
+		{name: "too low clamped", level: -7, want: CompressDefault, wantWarn: true},
+		{name: "huffman only clamped", level: gzip.HuffmanOnly, want: CompressDefault, wantWarn: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var out bytes.Buffer
+			previous := slog.Default()
+			slog.SetDefault(slog.New(slog.NewTextHandler(&out, nil)))
+			defer slog.SetDefault(previous)
+
+			if got := clampCompressLevel(tt.level); got != tt.want {
+				t.Errorf("clampCompressLevel(%d) = %d, want %d", tt.level, got, tt.want)
+			}
+			if warned := strings.Contains(out.String(), "compression level out of range"); warned != tt.wantWarn {
+				t.Errorf("warned = %v, want %v", warned, tt.wantWarn)
+			}
+		})
+	}
+}
+
+func TestCompressMiddlewareClampedLevelStillCompresses(t *testing.T) {
+	handler := CompressMiddleware(100)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(strings.Repeat("compress me ", 100)))
+	}))
+
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req.Header.Set("Accept-Encoding", "gzip")
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+
+	gz, err := gzip.NewReader(rec.Body)
+	if err != nil {
+		t.Fatalf("response not gzipped: %v", err)
+	}
+	body, _ := io.ReadAll(gz)
+	if string(body) != strings.Repeat("compress me ", 100) {
+		t.Errorf("inflated body = %.40q", body)
+	}
+}
@@ -0,0 +1,42 @@
+package server
+
+import (
+	"mime"
+	"net/http"
+	"strings"
+
+	"github.com/himtar/go-boilerplate/pkg/response"
+)
+
+// ContentTypeMiddleware rejects requests with a body whose Content-Type isn't
+// one of types with a 415. Only the media type is compared, so
+// "application/json; charset=utf-8" matches "application/json", but a
+// charset other than UTF-8 is rejected. Bodyless requests pass through.
+func ContentTypeMiddleware(types ...string) func(http.Handler) http.Handler {
+	allowed := make(map[string]bool, len(types))
+	for _, t := range types {
+		allowed[strings.ToLower(strings.TrimSpace(t))] = true
+	}
+
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.ContentLength == 0 || r.Body == nil || r.Body == http.NoBody {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			mediaType, params, err := mime.ParseMediaType(r.Header.Get("Content-Type"))
+			if err != nil || !allowed[mediaType] {
+				response.Send(w, http.StatusUnsupportedMediaType, "Unsupported Content-Type", nil)
+				return
+			}
+
+			if charset, ok := params["charset"]; ok && !strings.EqualFold(charset, "utf-8") && !strings.EqualFold(charset, "utf8") {
+				response.Send(w, http.StatusUnsupportedMediaType, "Unsupported charset, use utf-8", nil)
+				return
+			}
+
+			next.ServeHTTP(w, r)
+		})
+	}
+}
@@ -0,0 +1,50 @@
+package server
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestContentTypeMiddleware(t *testing.T) {
+	tests := []struct {
+		name        string
+		contentType string
+		body        string
+		wantStatus  int
+	}{
+		{name: "plain media type", contentType: "application/json", body: "{}", wantStatus: http.StatusOK},
+		{name: "utf-8 charset", contentType: "application/json; charset=utf-8", body: "{}", wantStatus: http.StatusOK},
+		{name: "case and spacing", contentType: "Application/JSON ;  Charset=UTF-8", body: "{}", wantStatus: http.StatusOK},
+		{name: "utf8 alias", contentType: "application/json; charset=utf8", body: "{}", wantStatus: http.StatusOK},
+		{name: "wrong media type", contentType: "text/plain", body: "{}", wantStatus: http.StatusUnsupportedMediaType},
+		{name: "other charset", contentType: "application/json; charset=latin1", body: "{}", wantStatus: http.StatusUnsupportedMediaType},
+		{name: "malformed header", contentType: "application/json; charset", body: "{}", wantStatus: http.StatusUnsupportedMediaType},
+		{name: "missing header", body: "{}", wantStatus: http.StatusUnsupportedMediaType},
+		{name: "bodyless request", contentType: "text/plain", wantStatus: http.StatusOK},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			handler := ContentTypeMiddleware("application/json")(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+
+			var req *http.Request
+			if tt.body == "" {
+				req = httptest.NewRequest(http.MethodPost, "/orders", nil)
+			} else {
+				req = httptest.NewRequest(http.MethodPost, "/orders", strings.NewReader(tt.body))
+			}
+			if tt.contentType != "" {
+				req.Header.Set("Content-Type", tt.contentType)
+			}
+
+			rec := httptest.NewRecorder()
+			handler.ServeHTTP(rec, req)
+
+			if rec.Code != tt.wantStatus {
+				t.Errorf("status = %d, want %d", rec.Code, tt.wantStatus)
+			}
+		})
+	}
+}
@@ -0,0 +1,51 @@
+package server
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+// corsMaxAge is how long browsers may cache a preflight answer, in seconds.
+const corsMaxAge = 600
+
+// CORSMiddleware allows cross-origin requests from allowedOrigins, "*"
+// allowing any origin, and answers preflight requests itself. Requests from
+// other origins are served without CORS headers, so browsers block them.
+func CORSMiddleware(allowedOrigins []string) func(http.Handler) http.Handler {
+	allowed := make(map[string]bool, len(allowedOrigins))
+	for _, origin := range allowedOrigins {
+		allowed[strings.TrimSuffix(origin, "/")] = true
+	}
+
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			origin := r.Header.Get("Origin")
+			w.Header().Add("Vary", "Origin")
+
+			if origin == "" || !(allowed["*"] || allowed[origin]) {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			header := w.Header()
+			header.Set("Access-Control-Allow-Origin", origin)
+
+			// preflight, answered here so it never reaches the handlers
+			if r.Method == http.MethodOptions && r.Header.Get("Access-Control-Request-Method") != "" {
+				header.Add("Vary", "Access-Control-Request-Method")
+				header.Add("Vary", "Access-Control-Request-Headers")
+				header.Set("Access-Control-Allow-Methods", r.Header.Get("Access-Control-Request-Method"))
+				if requested := r.Header.Get("Access-Control-Request-Headers"); requested != "" {
+					header.Set("Access-Control-Allow-Headers", requested)
+				}
+				header.Set("Access-Control-Max-Age", strconv.Itoa(corsMaxAge))
+				w.WriteHeader(http.StatusNoContent)
+				return
+			}
+
+			header.Set("Access-Control-Expose-Headers", strings.Join([]string{TraceIDHeader, RequestIDHeader}, ", "))
+			next.ServeHTTP(w, r)
+		})
+	}
+}
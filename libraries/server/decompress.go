@@ -0,0 +1,54 @@
+package server
+
+import (
+	"bytes"
+	"compress/gzip"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/himtar/go-boilerplate/pkg/errors"
+)
+
+// DecompressMiddleware inflates gzip encoded request bodies for the handlers.
+// The inflated body is capped at maxBytes so a tiny compressed payload can't
+// expand to gigabytes, bigger ones are rejected with a 413 and broken gzip
+// with a 400. Other encodings are passed through untouched.
+func DecompressMiddleware(maxBytes int64) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if !strings.EqualFold(strings.TrimSpace(r.Header.Get("Content-Encoding")), "gzip") {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			gz, err := gzip.NewReader(r.Body)
+			if err != nil {
+				errors.BadRequest(w, "Invalid gzip body !")
+				return
+			}
+			defer gz.Close()
+
+			// read one byte past the cap to tell a body of exactly maxBytes from a bomb
+			body, err := io.ReadAll(io.LimitReader(gz, maxBytes+1))
+			if err != nil {
+				errors.BadRequest(w, "Invalid gzip body !")
+				return
+			}
+
+			if int64(len(body)) > maxBytes {
+				errors.PayloadTooLarge(w, "Decompressed body too large !")
+				return
+			}
+
+			r.Body.Close()
+			r.Body = io.NopCloser(bytes.NewReader(body))
+			r.ContentLength = int64(len(body))
+			r.Header.Set("Content-Length", strconv.Itoa(len(body)))
+			r.Header.Del("Content-Encoding")
+
+			next.ServeHTTP(w, r)
+		})
+	}
+}
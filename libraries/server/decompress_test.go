@@ -0,0 +1,81 @@
+package server
+
+import (
+	"bytes"
+	"compress/gzip"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func gzipped(t *testing.T, data []byte) []byte {
+	t.Helper()
+
+	var buf bytes.Buffer
+	gz := gzip.NewWriter(&buf)
+	if _, err := gz.Write(data); err != nil {
+		t.Fatal(err)
+	}
+	if err := gz.Close(); err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes()
+}
+
+func TestDecompressMiddleware(t *testing.T) {
+	const limit = 1 << 10
+
+	// zeros compress about a thousandfold, a couple of kilobytes inflate to a megabyte
+	bomb := gzipped(t, make([]byte, 1<<20))
+	if len(bomb) > 4<<10 {
+		t.Fatalf("bomb is %d bytes compressed, want a highly compressible payload", len(bomb))
+	}
+
+	tests := []struct {
+		name       string
+		body       []byte
+		encoding   string
+		wantStatus int
+		wantBody   string
+	}{
+		{name: "gzip body inflated", body: gzipped(t, []byte(`{"sku":"A-1"}`)), encoding: "gzip", wantStatus: http.StatusOK, wantBody: `{"sku":"A-1"}`},
+		{name: "exactly at the cap", body: gzipped(t, bytes.Repeat([]byte("a"), limit)), encoding: "GZIP", wantStatus: http.StatusOK, wantBody: strings.Repeat("a", limit)},
+		{name: "bomb rejected", body: bomb, encoding: "gzip", wantStatus: http.StatusRequestEntityTooLarge},
+		{name: "broken gzip", body: []byte("not gzip"), encoding: "gzip", wantStatus: http.StatusBadRequest},
+		{name: "plain body untouched", body: []byte(`{"sku":"A-1"}`), wantStatus: http.StatusOK, wantBody: `{"sku":"A-1"}`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var received string
+			var receivedEncoding string
+			handler := DecompressMiddleware(limit)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				body, _ := io.ReadAll(r.Body)
+				received, receivedEncoding = string(body), r.Header.Get("Content-Encoding")
+			}))
+
+			req := httptest.NewRequest(http.MethodPost, "/orders", bytes.NewReader(tt.body))
+			if tt.encoding != "" {
+				req.Header.Set("Content-Encoding", tt.encoding)
+			}
+			rec := httptest.NewRecorder()
+			handler.ServeHTTP(rec, req)
+
+			if rec.Code != tt.wantStatus {
+				t.Fatalf("status = %d, want %d", rec.Code, tt.wantStatus)
+			}
+			if tt.wantStatus != http.StatusOK {
+				return
+			}
+
+			if received != tt.wantBody {
+				t.Errorf("handler body = %.40q, want %.40q", received, tt.wantBody)
+			}
+			if receivedEncoding != "" {
+				t.Errorf("Content-Encoding = %q left on the inflated request", receivedEncoding)
+			}
+		})
+	}
+}
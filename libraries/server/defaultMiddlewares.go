@@ -0,0 +1,53 @@
+package server
+
+import (
+	"log/slog"
+	"net/http"
+
+	"github.com/go-chi/chi/middleware"
+	"github.com/himtar/go-boilerplate/pkg/logger"
+)
+
+// BuildDefaultMiddlewares assembles the standard middleware stack: start
+// time, trace ID, request ID, access log and recoverer, followed by the extras
+// the env asks for. TRUST_PROXY takes the client IP from X-Forwarded-For and
+// X-Real-IP, outside development responses get compressed,
+// CORS_ALLOWED_ORIGINS enables CORS and RATE_LIMIT_RPS a per client rate
+// limit. l receives the access log and panics, slog.Default() when nil.
+func BuildDefaultMiddlewares(env *Variables, l *logger.Logger) []func(http.Handler) http.Handler {
+	var base *slog.Logger
+	if l != nil {
+		base = l.Logger
+	}
+
+	mws := []func(http.Handler) http.Handler{
+		StartTimeMiddleware,
+		TraceIDMiddleware,
+		RequestIDMiddleware,
+	}
+
+	// without a proxy overwriting them the headers are client chosen, and
+	// would let anyone pick their rate limit key
+	if env.TrustProxy() {
+		mws = append(mws, middleware.RealIP)
+	}
+
+	mws = append(mws,
+		LoggerMiddleware(LoggerOptions{Logger: base}),
+		RecovererMiddleware(base),
+	)
+
+	mws = append(mws, EnvMiddlewares(env.Env())...)
+
+	if origins := env.CORSAllowedOrigins(); len(origins) > 0 {
+		mws = append(mws, CORSMiddleware(origins))
+	}
+
+	if rps := env.RateLimitRPS(); rps > 0 {
+		// a burst of one second worth of requests
+		limiter := NewTokenBucketLimiter(rps, rps)
+		mws = append(mws, CostRateLimitMiddleware(limiter, func(*http.Request) float64 { return 1 }))
+	}
+
+	return mws
+}
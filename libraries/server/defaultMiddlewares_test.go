@@ -0,0 +1,45 @@
+package server
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/himtar/go-boilerplate/pkg/logger"
+)
+
+func TestBuildDefaultMiddlewaresRateLimitKey(t *testing.T) {
+	tests := []struct {
+		name       string
+		trustProxy string
+		wantStatus int
+	}{
+		{name: "forwarded IP ignored without proxy", trustProxy: "false", wantStatus: http.StatusTooManyRequests},
+		{name: "forwarded IP used behind proxy", trustProxy: "true", wantStatus: http.StatusOK},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			env := &Variables{env: "development", rateLimitRPS: "1", trustProxy: tt.trustProxy}
+
+			var handler http.Handler = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {})
+			mws := BuildDefaultMiddlewares(env, logger.DiscardLogger())
+			for i := len(mws) - 1; i >= 0; i-- {
+				handler = mws[i](handler)
+			}
+
+			var status int
+			for _, forwardedFor := range []string{"203.0.113.1", "203.0.113.2"} {
+				req := httptest.NewRequest(http.MethodGet, "/", nil)
+				req.Header.Set("X-Forwarded-For", forwardedFor)
+				rec := httptest.NewRecorder()
+				handler.ServeHTTP(rec, req)
+				status = rec.Code
+			}
+
+			if status != tt.wantStatus {
+				t.Errorf("second request status = %d, want %d", status, tt.wantStatus)
+			}
+		})
+	}
+}
@@ -0,0 +1,47 @@
+package server
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/go-chi/chi"
+)
+
+// Deprecated returns middleware marking the route registered on r at pattern
+// as deprecated, adding the Deprecation and Sunset headers to its responses
+// so clients get warned to migrate. Other routes are left alone, e.g.
+// r.Use(r.Deprecated("/v1/users/{id}", sunset)).
+func (r *HTTPRouter) Deprecated(pattern string, sunset time.Time) func(http.Handler) http.Handler {
+	sunsetHeader := sunset.UTC().Format(http.TimeFormat)
+
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+			if r.routePattern(req) == pattern {
+				w.Header().Set("Deprecation", "true")
+				w.Header().Set("Sunset", sunsetHeader)
+			}
+
+			next.ServeHTTP(w, req)
+		})
+	}
+}
+
+// routePattern returns the pattern of the route on r that req is headed
+// for, "" when none matches. Middleware runs before chi routed the request,
+// so it is looked up the same way chi will.
+func (r *HTTPRouter) routePattern(req *http.Request) string {
+	path := req.URL.Path
+	if req.URL.RawPath != "" {
+		path = req.URL.RawPath
+	}
+	// a mounted router only sees what is left after its mount point
+	if rctx := chi.RouteContext(req.Context()); rctx != nil && rctx.RoutePath != "" {
+		path = rctx.RoutePath
+	}
+
+	match := chi.NewRouteContext()
+	if !r.Match(match, req.Method, path) {
+		return ""
+	}
+	return match.RoutePattern()
+}
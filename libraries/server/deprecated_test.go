@@ -0,0 +1,56 @@
+package server
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/go-chi/chi"
+)
+
+func TestDeprecated(t *testing.T) {
+	sunset := time.Date(2027, time.January, 31, 0, 0, 0, 0, time.FixedZone("CET", 3600))
+	noop := func(w http.ResponseWriter, r *http.Request) {}
+
+	app, err := NewHTTPRouter(chi.NewRouter(), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	app.Use(app.Deprecated("/v1/users", sunset))
+	app.Get("/v1/users", noop)
+	app.Get("/v2/users", noop)
+	app.Route("/api", func(r *HTTPRouter) {
+		r.Use(r.Deprecated("/users/{id}", sunset))
+		r.Get("/users/{id}", noop)
+		r.Get("/users", noop)
+	})
+
+	tests := []struct {
+		name            string
+		path            string
+		wantDeprecation string
+		wantSunset      string
+	}{
+		{name: "deprecated route", path: "/v1/users", wantDeprecation: "true", wantSunset: "Sat, 30 Jan 2027 23:00:00 GMT"},
+		{name: "other route", path: "/v2/users"},
+		{name: "deprecated route in sub-router", path: "/api/users/42", wantDeprecation: "true", wantSunset: "Sat, 30 Jan 2027 23:00:00 GMT"},
+		{name: "other route in sub-router", path: "/api/users"},
+		{name: "unknown route", path: "/v1/orders"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := httptest.NewRecorder()
+			app.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, tt.path, nil))
+
+			if got := rec.Header().Get("Deprecation"); got != tt.wantDeprecation {
+				t.Errorf("Deprecation = %q, want %q", got, tt.wantDeprecation)
+			}
+			if got := rec.Header().Get("Sunset"); got != tt.wantSunset {
+				t.Errorf("Sunset = %q, want %q", got, tt.wantSunset)
+			}
+		})
+	}
+}
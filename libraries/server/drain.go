@@ -0,0 +1,52 @@
+package server
+
+import (
+	"fmt"
+	"net/http"
+	"sync/atomic"
+
+	"github.com/himtar/go-boilerplate/pkg/errors"
+)
+
+// draining is flipped once the server starts a manual or signal driven drain.
+var draining atomic.Bool
+
+// notReady fails readiness only, see SetDraining.
+var notReady atomic.Bool
+
+// SetDraining marks the instance as draining for /readyz only, so the load
+// balancer stops routing to it while requests, /healthz included, are still
+// served. Unlike DrainHandler it can be turned off again.
+func SetDraining(enabled bool) {
+	notReady.Store(enabled)
+}
+
+// IsDraining reports whether the server has started draining.
+func IsDraining() bool {
+	return draining.Load()
+}
+
+// DrainHandler starts draining: readiness fails and new requests get a 503
+// while in-flight requests are left to complete. Mount it behind admin auth.
+func DrainHandler() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		draining.Store(true)
+
+		w.WriteHeader(http.StatusAccepted)
+		fmt.Fprintf(w, "Draining")
+	}
+}
+
+// DrainMiddleware rejects requests arriving after draining has started with a
+// 503. Liveness probes keep passing so the process isn't killed mid-drain.
+func DrainMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if IsDraining() && r.URL.Path != LivenessPath {
+			w.Header().Set("Connection", "close")
+			errors.ServiceUnavailable(w, "")
+			return
+		}
+
+		next.ServeHTTP(w, r)
+	})
+}
@@ -0,0 +1,117 @@
+package server
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+
+	"github.com/go-chi/chi"
+)
+
+func TestDrainHandler(t *testing.T) {
+	t.Cleanup(func() { draining.Store(false) })
+
+	started := make(chan struct{})
+	release := make(chan struct{})
+
+	app := chi.NewRouter()
+	app.Post("/admin/drain", DrainHandler())
+	app.Get("/slow", func(w http.ResponseWriter, r *http.Request) {
+		close(started)
+		<-release
+	})
+	app.Get("/items", func(w http.ResponseWriter, r *http.Request) {})
+
+	var served atomic.Uint64
+	handler, err := prepareServer(app, &served, false, nil, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	serve := func(method, path string) int {
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, httptest.NewRequest(method, path, nil))
+		return rec.Code
+	}
+
+	if status := serve(http.MethodGet, ReadinessPath); status != http.StatusOK {
+		t.Fatalf("readiness before drain = %d, want %d", status, http.StatusOK)
+	}
+
+	inFlight := make(chan int)
+	go func() { inFlight <- serve(http.MethodGet, "/slow") }()
+	<-started
+
+	if status := serve(http.MethodPost, "/admin/drain"); status != http.StatusAccepted {
+		t.Fatalf("drain = %d, want %d", status, http.StatusAccepted)
+	}
+
+	tests := []struct {
+		name       string
+		path       string
+		wantStatus int
+	}{
+		{name: "readiness fails", path: ReadinessPath, wantStatus: http.StatusServiceUnavailable},
+		{name: "new requests are rejected", path: "/items", wantStatus: http.StatusServiceUnavailable},
+		{name: "liveness keeps passing", path: LivenessPath, wantStatus: http.StatusOK},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if status := serve(http.MethodGet, tt.path); status != tt.wantStatus {
+				t.Errorf("%s = %d, want %d", tt.path, status, tt.wantStatus)
+			}
+		})
+	}
+
+	close(release)
+	if status := <-inFlight; status != http.StatusOK {
+		t.Errorf("in-flight request = %d, want %d", status, http.StatusOK)
+	}
+}
+
+func TestSetDraining(t *testing.T) {
+	t.Cleanup(func() { SetDraining(false) })
+
+	app := chi.NewRouter()
+	app.Get("/items", func(w http.ResponseWriter, r *http.Request) {})
+
+	var served atomic.Uint64
+	handler, err := prepareServer(app, &served, false, nil, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	serve := func(path string) int {
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, path, nil))
+		return rec.Code
+	}
+
+	tests := []struct {
+		name          string
+		draining      bool
+		wantReadiness int
+	}{
+		{name: "draining", draining: true, wantReadiness: http.StatusServiceUnavailable},
+		{name: "turned off again", draining: false, wantReadiness: http.StatusOK},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			SetDraining(tt.draining)
+
+			if status := serve(ReadinessPath); status != tt.wantReadiness {
+				t.Errorf("%s = %d, want %d", ReadinessPath, status, tt.wantReadiness)
+			}
+			// only readiness is affected, liveness and requests keep passing
+			if status := serve(LivenessPath); status != http.StatusOK {
+				t.Errorf("%s = %d, want %d", LivenessPath, status, http.StatusOK)
+			}
+			if status := serve("/items"); status != http.StatusOK {
+				t.Errorf("/items = %d, want %d", status, http.StatusOK)
+			}
+		})
+	}
+}
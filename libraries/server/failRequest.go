@@ -0,0 +1,40 @@
+package server
+
+import (
+	"log/slog"
+	"net/http"
+
+	"github.com/himtar/go-boilerplate/pkg/logger"
+	"github.com/himtar/go-boilerplate/pkg/response"
+)
+
+// FailRequest logs err with the request's trace and request IDs and sends the
+// client an error envelope with userMessage, in one call. 5xx are logged at
+// ERROR and anything else at WARN. err never reaches the client, an empty
+// userMessage falls back to the status text. A nil l uses the request's
+// logger, see logger.FromContext.
+func FailRequest(w http.ResponseWriter, r *http.Request, l *logger.Logger, status int, userMessage string, err error) {
+	if l == nil {
+		l = logger.FromContext(r.Context())
+	}
+
+	level := slog.LevelWarn
+	if status >= http.StatusInternalServerError {
+		level = slog.LevelError
+	}
+
+	l.Log(r.Context(), level, "request failed",
+		slog.Int("status", status),
+		slog.String("method", r.Method),
+		slog.String("path", r.URL.Path),
+		slog.String("trace_id", TraceID(r.Context())),
+		slog.String("request_id", RequestID(r.Context())),
+		slog.Any("error", err),
+	)
+
+	if userMessage == "" {
+		userMessage = http.StatusText(status)
+	}
+
+	response.Send(w, status, userMessage, nil)
+}
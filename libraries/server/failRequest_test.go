@@ -0,0 +1,77 @@
+package server
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/himtar/go-boilerplate/pkg/logger"
+)
+
+func TestFailRequest(t *testing.T) {
+	tests := []struct {
+		name        string
+		status      int
+		userMessage string
+		wantMessage string
+		wantLevel   string
+	}{
+		{name: "server error", status: http.StatusInternalServerError, userMessage: "Could not create order", wantMessage: "Could not create order", wantLevel: "ERROR"},
+		{name: "client error", status: http.StatusConflict, userMessage: "Order already exists", wantMessage: "Order already exists", wantLevel: "WARN"},
+		{name: "status text fallback", status: http.StatusBadGateway, wantMessage: "Bad Gateway", wantLevel: "ERROR"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var out bytes.Buffer
+			l := &logger.Logger{Logger: slog.New(slog.NewJSONHandler(&out, nil))}
+			err := errors.New("pq: duplicate key value violates unique constraint")
+
+			handler := TraceIDMiddleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				FailRequest(w, r, l, tt.status, tt.userMessage, err)
+			}))
+
+			req := httptest.NewRequest(http.MethodPost, "/orders", nil)
+			req.Header.Set(TraceIDHeader, "trace-123")
+			rec := httptest.NewRecorder()
+			handler.ServeHTTP(rec, req)
+
+			if rec.Code != tt.status {
+				t.Errorf("status = %d, want %d", rec.Code, tt.status)
+			}
+
+			var body struct {
+				Message string `json:"message"`
+			}
+			if err := json.Unmarshal(rec.Body.Bytes(), &body); err != nil {
+				t.Fatal(err)
+			}
+			if body.Message != tt.wantMessage {
+				t.Errorf("message = %q, want %q", body.Message, tt.wantMessage)
+			}
+			if strings.Contains(rec.Body.String(), "pq:") {
+				t.Errorf("response leaks the error: %s", rec.Body.String())
+			}
+
+			entries := logEntries(t, &out)
+			if len(entries) != 1 {
+				t.Fatalf("got %d log entries, want 1", len(entries))
+			}
+			entry := entries[0]
+			if entry["level"] != tt.wantLevel {
+				t.Errorf("level = %v, want %s", entry["level"], tt.wantLevel)
+			}
+			if entry["error"] != err.Error() {
+				t.Errorf("logged error = %v, want %q", entry["error"], err.Error())
+			}
+			if entry["trace_id"] != "trace-123" {
+				t.Errorf("trace_id = %v, want trace-123", entry["trace_id"])
+			}
+		})
+	}
+}
@@ -0,0 +1,105 @@
+package server
+
+import (
+	"context"
+	"net/http"
+	"sync"
+)
+
+const (
+	featureFlagsKey contextKey = "feature_flags"
+	flagSubjectKey  contextKey = "flag_subject"
+)
+
+// FlagSubject is who a flag gets evaluated for.
+type FlagSubject struct {
+	UserID   string
+	TenantID string
+}
+
+// WithFlagSubject returns a copy of ctx carrying the authenticated subject,
+// for the auth middleware to call before FeatureFlagMiddleware runs.
+func WithFlagSubject(ctx context.Context, subject FlagSubject) context.Context {
+	return context.WithValue(ctx, flagSubjectKey, subject)
+}
+
+// FlagSubjectFromContext returns the subject stored by WithFlagSubject, the
+// zero FlagSubject for anonymous requests.
+func FlagSubjectFromContext(r *http.Request) FlagSubject {
+	subject, _ := r.Context().Value(flagSubjectKey).(FlagSubject)
+	return subject
+}
+
+// FlagProvider decides whether a feature flag is on for a subject.
+type FlagProvider interface {
+	Enabled(ctx context.Context, name string, subject FlagSubject) bool
+}
+
+// StaticFlags is a FlagProvider serving the same fixed flags to everyone.
+type StaticFlags map[string]bool
+
+// Enabled reports the flag's fixed value, unknown flags are off.
+func (f StaticFlags) Enabled(_ context.Context, name string, _ FlagSubject) bool {
+	return f[name]
+}
+
+// requestFlags evaluates flags lazily and remembers the results, so a flag
+// keeps the same value for the whole request.
+type requestFlags struct {
+	provider FlagProvider
+	subject  FlagSubject
+
+	mu     sync.Mutex
+	values map[string]bool
+}
+
+func (f *requestFlags) enabled(ctx context.Context, name string) bool {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if value, ok := f.values[name]; ok {
+		return value
+	}
+
+	value := f.provider.Enabled(ctx, name, f.subject)
+	f.values[name] = value
+	return value
+}
+
+// FeatureFlagMiddleware makes flags from provider available to handlers
+// through FlagEnabled, evaluated for the subject returned by subjectFn. A nil
+// subjectFn uses FlagSubjectFromContext, never client headers, so clients
+// can't pick whose flags they get. A nil provider turns every flag off.
+func FeatureFlagMiddleware(provider FlagProvider, subjectFn func(r *http.Request) FlagSubject) func(http.Handler) http.Handler {
+	if provider == nil {
+		provider = StaticFlags{}
+	}
+
+	if subjectFn == nil {
+		subjectFn = FlagSubjectFromContext
+	}
+
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			flags := &requestFlags{
+				provider: provider,
+				subject:  subjectFn(r),
+				values:   make(map[string]bool),
+			}
+
+			ctx := context.WithValue(r.Context(), featureFlagsKey, flags)
+			next.ServeHTTP(w, r.WithContext(ctx))
+		})
+	}
+}
+
+// FlagEnabled reports whether the named flag is on for the current request.
+// It is always off outside of FeatureFlagMiddleware.
+func FlagEnabled(ctx context.Context, name string) bool {
+	flags, ok := ctx.Value(featureFlagsKey).(*requestFlags)
+	if !ok {
+		return false
+	}
+
+	return flags.enabled(ctx, name)
+}
@@ -0,0 +1,62 @@
+package server
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+// betaUsers turns "beta" on for user 42 only.
+type betaUsers struct{}
+
+func (betaUsers) Enabled(_ context.Context, name string, subject FlagSubject) bool {
+	return name == "beta" && subject.UserID == "42"
+}
+
+func TestFeatureFlagMiddleware(t *testing.T) {
+	tests := []struct {
+		name      string
+		subject   *FlagSubject
+		subjectFn func(r *http.Request) FlagSubject
+		header    string
+		want      bool
+	}{
+		{name: "subject from context", subject: &FlagSubject{UserID: "42"}, want: true},
+		{name: "other subject", subject: &FlagSubject{UserID: "7"}, want: false},
+		{name: "client header is ignored", header: "42", want: false},
+		{
+			name:      "custom subjectFn",
+			subjectFn: func(*http.Request) FlagSubject { return FlagSubject{UserID: "42"} },
+			want:      true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got bool
+			handler := FeatureFlagMiddleware(betaUsers{}, tt.subjectFn)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				got = FlagEnabled(r.Context(), "beta")
+			}))
+
+			req := httptest.NewRequest(http.MethodGet, "/", nil)
+			if tt.header != "" {
+				req.Header.Set("X-User-ID", tt.header)
+			}
+			if tt.subject != nil {
+				req = req.WithContext(WithFlagSubject(req.Context(), *tt.subject))
+			}
+			handler.ServeHTTP(httptest.NewRecorder(), req)
+
+			if got != tt.want {
+				t.Errorf("FlagEnabled() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFlagEnabledOutsideMiddleware(t *testing.T) {
+	if FlagEnabled(context.Background(), "beta") {
+		t.Error("flag enabled without FeatureFlagMiddleware")
+	}
+}
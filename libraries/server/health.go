@@ -0,0 +1,78 @@
+package server
+
+import (
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/himtar/go-boilerplate/pkg/errors"
+)
+
+// Probe paths mounted by BuildAndStartServer.
+const (
+	LivenessPath  = "/healthz"
+	ReadinessPath = "/readyz"
+)
+
+var (
+	readinessChecksMu sync.RWMutex
+	readinessChecks   = make(map[string]func() error)
+)
+
+// RegisterReadinessCheck adds a named check that must pass for the server to report ready.
+func RegisterReadinessCheck(name string, check func() error) {
+	readinessChecksMu.Lock()
+	defer readinessChecksMu.Unlock()
+
+	readinessChecks[name] = check
+}
+
+// failingReadinessChecks runs every registered check and describes the failures.
+func failingReadinessChecks() []string {
+	readinessChecksMu.RLock()
+	defer readinessChecksMu.RUnlock()
+
+	var failures []string
+	for name, check := range readinessChecks {
+		if err := check(); err != nil {
+			failures = append(failures, name+": "+err.Error())
+		}
+	}
+
+	sort.Strings(failures)
+	return failures
+}
+
+// ReadinessHandler reports 200 while the server accepts traffic and 503 while
+// warming up, once draining or when a registered readiness check fails.
+func ReadinessHandler() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if IsDraining() || notReady.Load() {
+			errors.ServiceUnavailable(w, "Draining")
+			return
+		}
+
+		if IsWarmingUp() {
+			errors.ServiceUnavailable(w, "Warming Up")
+			return
+		}
+
+		if failures := failingReadinessChecks(); len(failures) > 0 {
+			errors.ServiceUnavailable(w, "Not Ready: "+strings.Join(failures, "; "))
+			return
+		}
+
+		fmt.Fprintf(w, "Ready")
+	}
+}
+
+// LivenessHandler reports 200 as long as the process can serve requests,
+// draining included, since restarting a draining instance would cut the
+// requests it is finishing.
+func LivenessHandler() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprintf(w, "OK")
+	}
+}
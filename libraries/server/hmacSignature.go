@@ -0,0 +1,56 @@
+package server
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/himtar/go-boilerplate/pkg/helpers"
+	"github.com/himtar/go-boilerplate/pkg/response"
+)
+
+// HMACSignatureMiddleware verifies webhook payloads, e.g. from Stripe or
+// GitHub, against the hex encoded HMAC-SHA256 of the raw body found in
+// headerName. A "sha256=" prefix on the signature is accepted. Missing or
+// wrong signatures are rejected with a 401 and bodies over
+// helpers.MaxJSONBodyBytes with a 413, the body is restored for the handler
+// otherwise.
+func HMACSignatureMiddleware(secret []byte, headerName string) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			signature, err := hex.DecodeString(strings.TrimPrefix(r.Header.Get(headerName), "sha256="))
+			if err != nil || len(signature) == 0 {
+				response.Send(w, http.StatusUnauthorized, "Missing or malformed signature", nil)
+				return
+			}
+
+			body, err := io.ReadAll(http.MaxBytesReader(w, r.Body, helpers.MaxJSONBodyBytes))
+			if err != nil {
+				var tooLarge *http.MaxBytesError
+				if errors.As(err, &tooLarge) {
+					response.Send(w, http.StatusRequestEntityTooLarge, "Request body too large", nil)
+					return
+				}
+				response.SendBadRequest(w, "Unable to read request body")
+				return
+			}
+			r.Body.Close()
+
+			mac := hmac.New(sha256.New, secret)
+			mac.Write(body)
+
+			if !hmac.Equal(signature, mac.Sum(nil)) {
+				response.Send(w, http.StatusUnauthorized, "Invalid signature", nil)
+				return
+			}
+
+			r.Body = io.NopCloser(bytes.NewReader(body))
+			next.ServeHTTP(w, r)
+		})
+	}
+}
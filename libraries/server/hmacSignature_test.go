@@ -0,0 +1,67 @@
+package server
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/himtar/go-boilerplate/pkg/helpers"
+)
+
+func TestHMACSignatureMiddleware(t *testing.T) {
+	secret := []byte("whsec")
+	sign := func(body string) string {
+		mac := hmac.New(sha256.New, secret)
+		mac.Write([]byte(body))
+		return hex.EncodeToString(mac.Sum(nil))
+	}
+
+	const payload = `{"event":"paid"}`
+	tests := []struct {
+		name       string
+		body       string
+		signature  string
+		wantStatus int
+	}{
+		{name: "valid signature", body: payload, signature: sign(payload), wantStatus: http.StatusOK},
+		{name: "valid prefixed signature", body: payload, signature: "sha256=" + sign(payload), wantStatus: http.StatusOK},
+		{name: "tampered body", body: `{"event":"refunded"}`, signature: sign(payload), wantStatus: http.StatusUnauthorized},
+		{name: "missing header", body: payload, wantStatus: http.StatusUnauthorized},
+		{name: "malformed header", body: payload, signature: "not-hex", wantStatus: http.StatusUnauthorized},
+		{
+			name:       "body too large",
+			body:       strings.Repeat("x", helpers.MaxJSONBodyBytes+1),
+			signature:  sign("x"),
+			wantStatus: http.StatusRequestEntityTooLarge,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got string
+			handler := HMACSignatureMiddleware(secret, "X-Signature")(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				body, _ := io.ReadAll(r.Body)
+				got = string(body)
+			}))
+
+			req := httptest.NewRequest(http.MethodPost, "/webhooks", strings.NewReader(tt.body))
+			if tt.signature != "" {
+				req.Header.Set("X-Signature", tt.signature)
+			}
+			rec := httptest.NewRecorder()
+			handler.ServeHTTP(rec, req)
+
+			if rec.Code != tt.wantStatus {
+				t.Fatalf("status = %d, want %d", rec.Code, tt.wantStatus)
+			}
+			if tt.wantStatus == http.StatusOK && got != tt.body {
+				t.Errorf("handler body = %q, want %q", got, tt.body)
+			}
+		})
+	}
+}
@@ -0,0 +1,54 @@
+package server
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"runtime/debug"
+	"time"
+)
+
+// initialHookBackoff is the pause before the first retry of a failed hook, doubled on every retry.
+const initialHookBackoff = 100 * time.Millisecond
+
+// runHook calls hook, turning a panic into an error carrying the stack trace
+// so user code can't take the server down with it.
+func runHook(ctx context.Context, name string, hook func(ctx context.Context) error) (err error) {
+	if hook == nil {
+		return nil
+	}
+
+	defer func() {
+		if rec := recover(); rec != nil {
+			err = fmt.Errorf("%s hook panicked: %v\n%s", name, rec, debug.Stack())
+		}
+	}()
+
+	return hook(ctx)
+}
+
+// runHookWithRetry calls hook up to retries more times while it fails,
+// backing off exponentially, and gives up early once ctx is done.
+func runHookWithRetry(ctx context.Context, name string, hook func(ctx context.Context) error, retries int) error {
+	backoff := initialHookBackoff
+
+	for attempt := 0; ; attempt++ {
+		err := runHook(ctx, name, hook)
+		if err == nil || attempt >= retries {
+			return err
+		}
+
+		slog.Warn(name+" hook failed, retrying",
+			slog.Int("attempt", attempt+1),
+			slog.Duration("retry_in", backoff),
+			slog.String("error", err.Error()),
+		)
+
+		select {
+		case <-ctx.Done():
+			return fmt.Errorf("%w (gave up retrying: %v)", err, ctx.Err())
+		case <-time.After(backoff):
+		}
+		backoff *= 2
+	}
+}
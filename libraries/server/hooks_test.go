@@ -0,0 +1,201 @@
+package server
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"log/slog"
+	"net/http"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/go-chi/chi"
+	"github.com/himtar/go-boilerplate/pkg/logger"
+)
+
+func TestRunHook(t *testing.T) {
+	errBoom := errors.New("boom")
+
+	tests := []struct {
+		name    string
+		hook    func(ctx context.Context) error
+		wantErr string
+	}{
+		{name: "nil hook", hook: nil},
+		{name: "successful hook", hook: func(ctx context.Context) error { return nil }},
+		{name: "failing hook", hook: func(ctx context.Context) error { return errBoom }, wantErr: "boom"},
+		{name: "panicking hook", hook: func(ctx context.Context) error { panic("kaboom") }, wantErr: "startup hook panicked: kaboom"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := runHook(context.Background(), "startup", tt.hook)
+
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Errorf("err = %v, want nil", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("err = %v, want it to contain %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestRunHookPanicCarriesStack(t *testing.T) {
+	err := runHook(context.Background(), "shutdown", func(ctx context.Context) error {
+		panic("kaboom")
+	})
+
+	if err == nil || !strings.Contains(err.Error(), "runtime/debug.Stack") {
+		t.Errorf("err = %v, want a stack trace", err)
+	}
+}
+
+func TestRunHookWithRetry(t *testing.T) {
+	calls := 0
+	err := runHookWithRetry(context.Background(), "shutdown", func(ctx context.Context) error {
+		calls++
+		if calls < 3 {
+			return errors.New("not yet")
+		}
+		return nil
+	}, 2)
+
+	if err != nil {
+		t.Errorf("err = %v, want nil after retrying", err)
+	}
+	if calls != 3 {
+		t.Errorf("calls = %d, want 3", calls)
+	}
+}
+
+func TestRunHookWithRetryStopsAtDeadline(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+
+	calls := 0
+	err := runHookWithRetry(ctx, "shutdown", func(ctx context.Context) error {
+		calls++
+		return errors.New("still down")
+	}, 10)
+
+	if err == nil || !strings.Contains(err.Error(), "gave up retrying") {
+		t.Errorf("err = %v, want a gave up retrying error", err)
+	}
+	if calls != 1 {
+		t.Errorf("calls = %d, want 1 before the deadline hit", calls)
+	}
+}
+
+func TestStartForTestPanickingStartupHook(t *testing.T) {
+	cfg := &ServerConfig{OnStartup: func(ctx context.Context) error { panic("no database") }}
+
+	_, _, err := StartForTest(context.Background(), cfg, chi.NewRouter())
+	if err == nil || !strings.Contains(err.Error(), "no database") {
+		t.Errorf("err = %v, want the startup panic as an error", err)
+	}
+}
+
+func TestShutdownHookRetries(t *testing.T) {
+	tests := []struct {
+		name       string
+		failures   int32
+		retries    int
+		wantCalls  int32
+		wantGaveUp bool
+	}{
+		{name: "fails then succeeds", failures: 2, retries: 3, wantCalls: 3},
+		{name: "retries exhausted", failures: 10, retries: 2, wantCalls: 3, wantGaveUp: true},
+		{name: "no retries", failures: 1, retries: 0, wantCalls: 1, wantGaveUp: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var out bytes.Buffer
+			previous := slog.Default()
+			slog.SetDefault(slog.New(slog.NewJSONHandler(&out, nil)))
+			defer slog.SetDefault(previous)
+
+			var calls atomic.Int32
+			cfg := &ServerConfig{
+				Logger:              logger.DiscardLogger(),
+				ShutdownHookRetries: tt.retries,
+				OnShutdown: func(ctx context.Context) error {
+					if calls.Add(1) <= tt.failures {
+						return errors.New("db close timed out")
+					}
+					return nil
+				},
+			}
+
+			_, stop, err := StartForTest(context.Background(), cfg, chi.NewRouter())
+			if err != nil {
+				t.Fatal(err)
+			}
+			stop()
+
+			if got := calls.Load(); got != tt.wantCalls {
+				t.Errorf("hook calls = %d, want %d", got, tt.wantCalls)
+			}
+
+			gaveUp := strings.Contains(out.String(), "shutdown hook failed, closing anyway")
+			if gaveUp != tt.wantGaveUp {
+				t.Errorf("gave up = %v, want %v: %s", gaveUp, tt.wantGaveUp, out.String())
+			}
+		})
+	}
+}
+
+func TestShutdownForceClosesAfterDeadline(t *testing.T) {
+	started := make(chan struct{})
+	release := make(chan struct{})
+	t.Cleanup(func() { close(release) })
+
+	app := chi.NewRouter()
+	app.Get("/stuck", func(w http.ResponseWriter, r *http.Request) {
+		close(started)
+		<-release
+	})
+
+	cfg := &ServerConfig{Logger: logger.DiscardLogger(), ShutdownTimeout: 100 * time.Millisecond}
+	addr, stop, err := StartForTest(context.Background(), cfg, app)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	requestDone := make(chan error, 1)
+	go func() {
+		res, err := http.Get("http://" + addr + "/stuck")
+		if err == nil {
+			res.Body.Close()
+		}
+		requestDone <- err
+	}()
+	<-started
+
+	stopped := make(chan struct{})
+	go func() {
+		stop()
+		close(stopped)
+	}()
+
+	select {
+	case <-stopped:
+	case <-time.After(2 * time.Second):
+		t.Fatal("shutdown waited on the stuck handler past its deadline")
+	}
+
+	select {
+	case err := <-requestDone:
+		if err == nil {
+			t.Error("stuck request completed, want its connection closed")
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("stuck request's connection left open after shutdown")
+	}
+}
@@ -0,0 +1,69 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"net/http"
+
+	"github.com/go-chi/chi"
+	"github.com/himtar/go-boilerplate/pkg/logger"
+)
+
+// HTTPRouter is a chi router carrying the logger its handlers and middleware
+// log with. Sub-routers created through Route and Group share it, and
+// requests routed through it carry it for logger.FromContext.
+type HTTPRouter struct {
+	chi.Router
+	logger *logger.Logger
+}
+
+// NewHTTPRouter wraps mux, which must have no routes yet, logging with l.
+// With a nil l requests keep the logger of the server the router is mounted
+// on, and Logger falls back to one writing through slog.Default().
+func NewHTTPRouter(mux *chi.Mux, l *logger.Logger) (*HTTPRouter, error) {
+	// chi panics on middleware added after the first route
+	if len(mux.Routes()) > 0 {
+		return nil, errors.New("http router needs a mux without routes")
+	}
+
+	if l != nil {
+		mux.Use(func(next http.Handler) http.Handler {
+			return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				next.ServeHTTP(w, r.WithContext(logger.NewContext(r.Context(), l)))
+			})
+		})
+	}
+
+	return &HTTPRouter{Router: mux, logger: l}, nil
+}
+
+// Logger returns the router's logger.
+func (r *HTTPRouter) Logger() *logger.Logger {
+	if r.logger == nil {
+		return logger.FromContext(context.Background())
+	}
+	return r.logger
+}
+
+// Route mounts a sub-router at pattern, sharing r's logger, and lets fn
+// register its routes.
+func (r *HTTPRouter) Route(pattern string, fn func(r *HTTPRouter)) *HTTPRouter {
+	child := &HTTPRouter{Router: chi.NewRouter(), logger: r.logger}
+	if fn != nil {
+		fn(child)
+	}
+
+	r.Mount(pattern, child)
+	return child
+}
+
+// Group creates an inline router sharing r's path and logger, so fn can add
+// middleware to some routes only.
+func (r *HTTPRouter) Group(fn func(r *HTTPRouter)) *HTTPRouter {
+	child := &HTTPRouter{Router: r.With(), logger: r.logger}
+	if fn != nil {
+		fn(child)
+	}
+
+	return child
+}
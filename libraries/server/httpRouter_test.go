@@ -0,0 +1,115 @@
+package server
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+
+	"github.com/go-chi/chi"
+	"github.com/himtar/go-boilerplate/pkg/logger"
+)
+
+func TestHTTPRouterPropagatesLogger(t *testing.T) {
+	parentLogger := logger.DiscardLogger()
+	root, err := NewHTTPRouter(chi.NewRouter(), parentLogger)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var routed, grouped, nested *HTTPRouter
+	var fromContext *logger.Logger
+
+	root.Route("/api", func(r *HTTPRouter) {
+		routed = r
+		r.Group(func(r *HTTPRouter) {
+			grouped = r
+			r.Route("/v1", func(r *HTTPRouter) {
+				nested = r
+				r.Get("/ping", func(w http.ResponseWriter, req *http.Request) {
+					fromContext = logger.FromContext(req.Context())
+				})
+			})
+		})
+	})
+
+	tests := []struct {
+		name   string
+		router *HTTPRouter
+	}{
+		{name: "Route", router: routed},
+		{name: "Group", router: grouped},
+		{name: "Route inside Group", router: nested},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if tt.router == nil || tt.router.Logger() == nil {
+				t.Fatal("child router has no logger")
+			}
+			if tt.router.Logger() != parentLogger {
+				t.Error("child router logger differs from the parent's")
+			}
+		})
+	}
+
+	rec := httptest.NewRecorder()
+	root.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/api/v1/ping", nil))
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status = %d, want %d", rec.Code, http.StatusOK)
+	}
+	if fromContext != parentLogger {
+		t.Error("handler context does not carry the router logger")
+	}
+}
+
+func TestNewHTTPRouterDefaultsLogger(t *testing.T) {
+	r, err := NewHTTPRouter(chi.NewRouter(), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if r.Logger() == nil {
+		t.Error("router without a logger")
+	}
+}
+
+func TestNewHTTPRouterWithoutLoggerKeepsServerLogger(t *testing.T) {
+	serverLogger := logger.DiscardLogger()
+
+	app := chi.NewRouter()
+	r, err := NewHTTPRouter(app, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var fromContext *logger.Logger
+	r.Get("/ping", func(w http.ResponseWriter, req *http.Request) {
+		fromContext = logger.FromContext(req.Context())
+	})
+
+	var served atomic.Uint64
+	handler, err := prepareServer(app, &served, false, nil, serverLogger)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/ping", nil))
+
+	if fromContext != serverLogger {
+		t.Error("handler context does not carry the server logger")
+	}
+}
+
+func TestNewHTTPRouterRejectsRoutedMux(t *testing.T) {
+	mux := chi.NewRouter()
+	mux.Get("/", func(w http.ResponseWriter, r *http.Request) {})
+
+	r, err := NewHTTPRouter(mux, logger.DiscardLogger())
+	if err == nil {
+		t.Fatal("expected an error for a mux with routes")
+	}
+	if r != nil {
+		t.Error("router returned along with the error")
+	}
+}
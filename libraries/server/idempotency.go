@@ -0,0 +1,226 @@
+package server
+
+import (
+	"bytes"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/himtar/go-boilerplate/pkg/response"
+)
+
+// IdempotencyKeyHeader is the request header carrying the client supplied idempotency key.
+const IdempotencyKeyHeader = "Idempotency-Key"
+
+// CachedResponse is a response captured on the first execution of an idempotent request.
+type CachedResponse struct {
+	StatusCode int
+	Header     http.Header
+	Body       []byte
+}
+
+// IdempotencyStore persists captured responses keyed by idempotency key.
+type IdempotencyStore interface {
+	Get(key string) (*CachedResponse, bool)
+	Set(key string, res *CachedResponse)
+
+	// Reserve marks key as in flight, false when it already is or holds a response.
+	Reserve(key string) bool
+
+	// Release drops a reservation that never got a response, e.g. after a panic.
+	Release(key string)
+}
+
+// sweepIdempotencyEvery is how often expired entries are swept from a MemoryIdempotencyStore.
+const sweepIdempotencyEvery = time.Minute
+
+type idempotencyEntry struct {
+	res       *CachedResponse // nil while the request is in flight
+	expiresAt time.Time
+}
+
+// MemoryIdempotencyStore is an in-memory IdempotencyStore whose entries expire after a TTL.
+type MemoryIdempotencyStore struct {
+	mu        sync.Mutex
+	ttl       time.Duration
+	entries   map[string]idempotencyEntry
+	lastSweep time.Time
+}
+
+// NewMemoryIdempotencyStore creates a new instance of MemoryIdempotencyStore.
+func NewMemoryIdempotencyStore(ttl time.Duration) *MemoryIdempotencyStore {
+	return &MemoryIdempotencyStore{
+		ttl:       ttl,
+		entries:   make(map[string]idempotencyEntry),
+		lastSweep: time.Now(),
+	}
+}
+
+// Get returns the cached response for key, dropping it if it has expired.
+// Keys still in flight have no response yet.
+func (s *MemoryIdempotencyStore) Get(key string) (*CachedResponse, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	entry, ok := s.entries[key]
+	if !ok {
+		return nil, false
+	}
+
+	if time.Now().After(entry.expiresAt) {
+		delete(s.entries, key)
+		return nil, false
+	}
+
+	return entry.res, entry.res != nil
+}
+
+// Set stores the response for key until the TTL elapses.
+func (s *MemoryIdempotencyStore) Set(key string, res *CachedResponse) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := time.Now()
+	s.sweep(now)
+	s.entries[key] = idempotencyEntry{res: res, expiresAt: now.Add(s.ttl)}
+}
+
+// Reserve marks key as in flight unless an unexpired entry exists.
+func (s *MemoryIdempotencyStore) Reserve(key string) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := time.Now()
+	s.sweep(now)
+
+	if entry, ok := s.entries[key]; ok && now.Before(entry.expiresAt) {
+		return false
+	}
+
+	s.entries[key] = idempotencyEntry{expiresAt: now.Add(s.ttl)}
+	return true
+}
+
+// Release drops key if it is still in flight, stored responses are kept.
+func (s *MemoryIdempotencyStore) Release(key string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if entry, ok := s.entries[key]; ok && entry.res == nil {
+		delete(s.entries, key)
+	}
+}
+
+// sweep drops expired entries, at most once per sweepIdempotencyEvery, so
+// keys that are never read again don't pile up.
+func (s *MemoryIdempotencyStore) sweep(now time.Time) {
+	if now.Sub(s.lastSweep) < sweepIdempotencyEvery {
+		return
+	}
+	s.lastSweep = now
+
+	for key, entry := range s.entries {
+		if now.After(entry.expiresAt) {
+			delete(s.entries, key)
+		}
+	}
+}
+
+// captureWriter writes through to the underlying writer while keeping a copy of the response.
+type captureWriter struct {
+	http.ResponseWriter
+	status int
+	body   bytes.Buffer
+}
+
+func (c *captureWriter) WriteHeader(status int) {
+	if c.status == 0 {
+		c.status = status
+	}
+	c.ResponseWriter.WriteHeader(status)
+}
+
+func (c *captureWriter) Write(b []byte) (int, error) {
+	if c.status == 0 {
+		c.status = http.StatusOK
+	}
+	c.body.Write(b)
+	return c.ResponseWriter.Write(b)
+}
+
+// Unwrap lets http.ResponseController and the trace ID lookup reach the underlying writer.
+func (c *captureWriter) Unwrap() http.ResponseWriter {
+	return c.ResponseWriter
+}
+
+// IdempotencyMiddleware replays the cached response for requests carrying an
+// already seen Idempotency-Key header, and answers 409 while the first request
+// with that key is still running. Only the given methods are considered,
+// POST and PATCH by default. A nil store falls back to a 24h in-memory store.
+func IdempotencyMiddleware(store IdempotencyStore, methods ...string) func(http.Handler) http.Handler {
+	if store == nil {
+		store = NewMemoryIdempotencyStore(24 * time.Hour)
+	}
+
+	if len(methods) == 0 {
+		methods = []string{http.MethodPost, http.MethodPatch}
+	}
+
+	allowed := make(map[string]bool, len(methods))
+	for _, method := range methods {
+		allowed[method] = true
+	}
+
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			key := r.Header.Get(IdempotencyKeyHeader)
+			if key == "" || !allowed[r.Method] {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			// scope the key to the endpoint so it can't replay another route's response
+			storeKey := r.Method + " " + r.URL.Path + " " + key
+
+			if cached, ok := store.Get(storeKey); ok {
+				replayResponse(w, cached)
+				return
+			}
+
+			if !store.Reserve(storeKey) {
+				// the first request may have finished between Get and Reserve
+				if cached, ok := store.Get(storeKey); ok {
+					replayResponse(w, cached)
+					return
+				}
+
+				response.Send(w, http.StatusConflict, "A request with this Idempotency-Key is still in progress", nil)
+				return
+			}
+			// frees the key when the handler panics, a no-op once the response is stored
+			defer store.Release(storeKey)
+
+			cw := &captureWriter{ResponseWriter: w}
+			next.ServeHTTP(cw, r)
+
+			if cw.status == 0 {
+				cw.status = http.StatusOK
+			}
+
+			store.Set(storeKey, &CachedResponse{
+				StatusCode: cw.status,
+				Header:     w.Header().Clone(),
+				Body:       cw.body.Bytes(),
+			})
+		})
+	}
+}
+
+// replayResponse writes a cached response to w.
+func replayResponse(w http.ResponseWriter, cached *CachedResponse) {
+	for name, values := range cached.Header {
+		w.Header()[name] = values
+	}
+	w.WriteHeader(cached.StatusCode)
+	w.Write(cached.Body)
+}
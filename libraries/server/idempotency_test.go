@@ -0,0 +1,129 @@
+package server
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestIdempotencyMiddleware(t *testing.T) {
+	tests := []struct {
+		name      string
+		method    string
+		key       string
+		wantCalls int32
+	}{
+		{name: "replays POST with key", method: http.MethodPost, key: "abc", wantCalls: 1},
+		{name: "ignores POST without key", method: http.MethodPost, wantCalls: 2},
+		{name: "ignores GET", method: http.MethodGet, key: "abc", wantCalls: 2},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var calls atomic.Int32
+			handler := IdempotencyMiddleware(nil)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				n := calls.Add(1)
+				w.WriteHeader(http.StatusCreated)
+				w.Write([]byte(strings.Repeat("x", int(n))))
+			}))
+
+			var bodies []string
+			for i := 0; i < 2; i++ {
+				req := httptest.NewRequest(tt.method, "/orders", nil)
+				if tt.key != "" {
+					req.Header.Set(IdempotencyKeyHeader, tt.key)
+				}
+				rec := httptest.NewRecorder()
+				handler.ServeHTTP(rec, req)
+
+				if rec.Code != http.StatusCreated {
+					t.Fatalf("status = %d, want %d", rec.Code, http.StatusCreated)
+				}
+				bodies = append(bodies, rec.Body.String())
+			}
+
+			if got := calls.Load(); got != tt.wantCalls {
+				t.Errorf("handler calls = %d, want %d", got, tt.wantCalls)
+			}
+			if tt.wantCalls == 1 && bodies[0] != bodies[1] {
+				t.Errorf("replayed body = %q, want %q", bodies[1], bodies[0])
+			}
+		})
+	}
+}
+
+func TestIdempotencyMiddlewareConflictWhileInFlight(t *testing.T) {
+	started := make(chan struct{})
+	release := make(chan struct{})
+	handler := IdempotencyMiddleware(nil)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		close(started)
+		<-release
+		w.WriteHeader(http.StatusCreated)
+	}))
+
+	newRequest := func() *http.Request {
+		req := httptest.NewRequest(http.MethodPost, "/orders", nil)
+		req.Header.Set(IdempotencyKeyHeader, "abc")
+		return req
+	}
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		handler.ServeHTTP(httptest.NewRecorder(), newRequest())
+	}()
+	<-started
+
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, newRequest())
+	if rec.Code != http.StatusConflict {
+		t.Errorf("concurrent request status = %d, want %d", rec.Code, http.StatusConflict)
+	}
+
+	close(release)
+	<-done
+
+	rec = httptest.NewRecorder()
+	handler.ServeHTTP(rec, newRequest())
+	if rec.Code != http.StatusCreated {
+		t.Errorf("replayed status = %d, want %d", rec.Code, http.StatusCreated)
+	}
+}
+
+func TestIdempotencyMiddlewareReleasesKeyOnPanic(t *testing.T) {
+	store := NewMemoryIdempotencyStore(time.Hour)
+	handler := IdempotencyMiddleware(store)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		panic("boom")
+	}))
+
+	req := httptest.NewRequest(http.MethodPost, "/orders", nil)
+	req.Header.Set(IdempotencyKeyHeader, "abc")
+
+	func() {
+		defer func() { recover() }()
+		handler.ServeHTTP(httptest.NewRecorder(), req)
+	}()
+
+	if !store.Reserve("POST /orders abc") {
+		t.Error("key still reserved after the handler panicked")
+	}
+}
+
+func TestMemoryIdempotencyStoreSweepsExpiredEntries(t *testing.T) {
+	store := NewMemoryIdempotencyStore(time.Millisecond)
+	store.Set("old", &CachedResponse{StatusCode: http.StatusOK})
+
+	time.Sleep(2 * time.Millisecond)
+	store.lastSweep = time.Now().Add(-sweepIdempotencyEvery)
+	store.Set("new", &CachedResponse{StatusCode: http.StatusOK})
+
+	if _, ok := store.entries["old"]; ok {
+		t.Error("expired entry survived the sweep")
+	}
+	if _, ok := store.entries["new"]; !ok {
+		t.Error("fresh entry was swept")
+	}
+}
@@ -1,8 +1,13 @@
 package server
 
 import (
-	"log"
+	"errors"
+	"io/fs"
+	"log/slog"
 	"os"
+	"strconv"
+	"strings"
+	"time"
 
 	"github.com/joho/godotenv"
 )
@@ -10,25 +15,57 @@ import (
 // Variables is an immutable struct representing configuration variables.
 type Variables struct {
 	env    string
+	envSet bool
 	dbURI  string
 	db     string
 	port   string
+	moduleName string
+	shutdownTimeoutMS string
+	corsAllowedOrigins string
+	rateLimitRPS string
+	trustProxy string
 }
 
-// function to load env variables
+// envKeys are the variables LoadENVVariables reads.
+var envKeys = []string{"ENV", "DB_URI", "DB", "PORT", "MODULE_NAME", "SHUTDOWN_TIMEOUT_MS", "CORS_ALLOWED_ORIGINS", "RATE_LIMIT_RPS", "TRUST_PROXY"}
+
+// function to load env variables.
+// A missing .env file is fine when the configuration comes from real
+// environment variables, e.g. in containers, it is only fatal when neither
+// is there. A .env file that can't be parsed is always fatal.
 func LoadENVVariables() *Variables {
 
 	// Load variables from the .env file
     if err := godotenv.Load(); err != nil {
-		log.Fatalf("Error loading .env file: %v", err)
+		if !errors.Is(err, fs.ErrNotExist) || !hasEnvVariables() {
+			slog.Error("Error loading .env file", slog.String("error", err.Error()))
+			os.Exit(1)
+		}
+		slog.Warn("No .env file, using environment variables only")
     }
 
 	return &Variables{
 		env:   getEnvOrDefault("ENV", "development"),
+		envSet: os.Getenv("ENV") != "",
 		dbURI: getEnvOrDefault("DB_URI", ""),
 		db:    getEnvOrDefault("DB", ""),
 		port:  getEnvOrDefault("PORT", ":8080"),
+		moduleName: getEnvOrDefault("MODULE_NAME", ""),
+		shutdownTimeoutMS: getEnvOrDefault("SHUTDOWN_TIMEOUT_MS", "5000"),
+		corsAllowedOrigins: getEnvOrDefault("CORS_ALLOWED_ORIGINS", ""),
+		rateLimitRPS: getEnvOrDefault("RATE_LIMIT_RPS", "0"),
+		trustProxy: getEnvOrDefault("TRUST_PROXY", "false"),
+	}
+}
+
+// hasEnvVariables reports whether any of envKeys is set in the environment.
+func hasEnvVariables() bool {
+	for _, key := range envKeys {
+		if _, ok := os.LookupEnv(key); ok {
+			return true
+		}
 	}
+	return false
 }
 
 // getEnvOrDefault retrieves the value of the environment variable or returns the default value.
@@ -45,6 +82,12 @@ func (v *Variables) Env() string {
 	return v.env
 }
 
+// Profiling reports whether pprof gets mounted at /debug, only when ENV is
+// explicitly set to development rather than defaulted to it.
+func (v *Variables) Profiling() bool {
+	return v.envSet && isDevelopment(v.env)
+}
+
 func (v *Variables) DBURI() string {
 	return v.dbURI
 }
@@ -55,4 +98,56 @@ func (v *Variables) DB() string {
 
 func (v *Variables) Port() string {
 	return v.port
-}
\ No newline at end of file
+}
+
+func (v *Variables) ModuleName() string {
+	return v.moduleName
+}
+
+// ShutdownTimeout returns SHUTDOWN_TIMEOUT_MS as a duration, 5s if it isn't a number.
+func (v *Variables) ShutdownTimeout() time.Duration {
+	ms, err := strconv.Atoi(v.shutdownTimeoutMS)
+	if err != nil {
+		slog.Warn("Invalid SHUTDOWN_TIMEOUT_MS, using 5000", slog.String("value", v.shutdownTimeoutMS))
+		return 5 * time.Second
+	}
+	return time.Duration(ms) * time.Millisecond
+}
+
+// CORSAllowedOrigins returns the comma separated CORS_ALLOWED_ORIGINS, nil when unset.
+func (v *Variables) CORSAllowedOrigins() []string {
+	var origins []string
+	for _, origin := range strings.Split(v.corsAllowedOrigins, ",") {
+		if origin = strings.TrimSpace(origin); origin != "" {
+			origins = append(origins, origin)
+		}
+	}
+	return origins
+}
+
+// RateLimitRPS returns RATE_LIMIT_RPS, the requests per second allowed per
+// client, 0 (no limit) if it isn't a number.
+func (v *Variables) RateLimitRPS() float64 {
+	if v.rateLimitRPS == "" {
+		return 0
+	}
+
+	rps, err := strconv.ParseFloat(v.rateLimitRPS, 64)
+	if err != nil || rps < 0 {
+		slog.Warn("Invalid RATE_LIMIT_RPS, rate limiting disabled", slog.String("value", v.rateLimitRPS))
+		return 0
+	}
+	return rps
+}
+
+// TrustProxy reports whether TRUST_PROXY is set, i.e. the service only runs
+// behind a proxy that overwrites X-Forwarded-For and X-Real-IP. Clients can
+// set those headers themselves otherwise.
+func (v *Variables) TrustProxy() bool {
+	trust, err := strconv.ParseBool(v.trustProxy)
+	if err != nil {
+		slog.Warn("Invalid TRUST_PROXY, proxy headers ignored", slog.String("value", v.trustProxy))
+		return false
+	}
+	return trust
+}
@@ -0,0 +1,119 @@
+package server
+
+import (
+	"encoding/json"
+	"log/slog"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/himtar/go-boilerplate/pkg/logger"
+)
+
+// loadEnvChildEnv marks the process TestLoadENVVariablesFailureIsStructured
+// re-executes to hit the fatal env load path.
+const loadEnvChildEnv = "SERVER_TEST_LOAD_ENV_CHILD"
+
+func TestLoadENVVariablesFailureIsStructured(t *testing.T) {
+	if os.Getenv(loadEnvChildEnv) != "" {
+		// same order as BuildAndStartServer: bootstrap logger first, then the env
+		slog.SetDefault(logger.Bootstrap())
+		LoadENVVariables()
+		return
+	}
+
+	// no .env file and none of the variables set, so loading is fatal
+	cmd := exec.Command(os.Args[0], "-test.run=^TestLoadENVVariablesFailureIsStructured$")
+	cmd.Dir = t.TempDir()
+	cmd.Env = append(withoutEnvKeys(os.Environ()), loadEnvChildEnv+"=1")
+
+	out, err := cmd.Output()
+	if exitErr, ok := err.(*exec.ExitError); !ok || exitErr.ExitCode() != 1 {
+		t.Fatalf("child exited with %v, want status 1", err)
+	}
+
+	var entry map[string]interface{}
+	line, _, _ := strings.Cut(string(out), "\n")
+	if err := json.Unmarshal([]byte(line), &entry); err != nil {
+		t.Fatalf("env load failure not structured: %q", out)
+	}
+
+	if entry["level"] != "ERROR" || entry["msg"] != "Error loading .env file" {
+		t.Errorf("entry = %v, want the env load error", entry)
+	}
+	if entry["error"] == nil || entry["service"] == nil {
+		t.Errorf("entry = %v, want error and service fields", entry)
+	}
+}
+
+func TestLoadENVVariables(t *testing.T) {
+	tests := []struct {
+		name     string
+		dotenv   string // no .env file when empty
+		env      map[string]string
+		wantPort string
+		wantEnv  string
+	}{
+		{name: "no .env, env variables set", env: map[string]string{"PORT": ":9090", "ENV": "production"}, wantPort: ":9090", wantEnv: "production"},
+		{name: ".env file", dotenv: "PORT=:7070\nENV=staging\n", wantPort: ":7070", wantEnv: "staging"},
+		{name: "env variables win over .env", dotenv: "PORT=:7070\n", env: map[string]string{"PORT": ":9090"}, wantPort: ":9090", wantEnv: "development"},
+	}
+
+	wd, err := os.Getwd()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.Chdir(wd)
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// t.Setenv restores the variables, including those .env sets
+			for _, key := range envKeys {
+				t.Setenv(key, "")
+				os.Unsetenv(key)
+			}
+			for key, value := range tt.env {
+				t.Setenv(key, value)
+			}
+
+			dir := t.TempDir()
+			if tt.dotenv != "" {
+				if err := os.WriteFile(filepath.Join(dir, ".env"), []byte(tt.dotenv), 0o644); err != nil {
+					t.Fatal(err)
+				}
+			}
+			if err := os.Chdir(dir); err != nil {
+				t.Fatal(err)
+			}
+
+			env := LoadENVVariables()
+			if env.Port() != tt.wantPort {
+				t.Errorf("Port() = %q, want %q", env.Port(), tt.wantPort)
+			}
+			if env.Env() != tt.wantEnv {
+				t.Errorf("Env() = %q, want %q", env.Env(), tt.wantEnv)
+			}
+		})
+	}
+}
+
+// withoutEnvKeys drops the variables LoadENVVariables reads from environ.
+func withoutEnvKeys(environ []string) []string {
+	var kept []string
+	for _, kv := range environ {
+		key, _, _ := strings.Cut(kv, "=")
+
+		known := false
+		for _, envKey := range envKeys {
+			if key == envKey {
+				known = true
+			}
+		}
+		if !known {
+			kept = append(kept, kv)
+		}
+	}
+	return kept
+}
@@ -0,0 +1,155 @@
+package server
+
+import (
+	"log/slog"
+	"net/http"
+	"sort"
+	"sync/atomic"
+	"time"
+
+	"github.com/go-chi/chi"
+)
+
+// LoggerOptions configures LoggerMiddleware.
+type LoggerOptions struct {
+	// Logger receives the access log entries, slog.Default() when nil.
+	Logger *slog.Logger
+
+	// SlowThreshold, when set, additionally logs requests taking longer than it at WARN.
+	SlowThreshold time.Duration
+
+	// LogResponseBody adds the response body to the entry, cut at MaxBodyBytes
+	// with body_truncated set. Streaming responses are never captured, encoded
+	// ones get body_encoding instead.
+	LogResponseBody bool
+
+	// MaxBodyBytes caps the logged body, defaultMaxLoggedBodyBytes when zero.
+	MaxBodyBytes int
+
+	// SampleSuccessEvery logs only 1 in N 2xx requests, for high traffic
+	// deployments. Other statuses and slow requests are always logged. Zero or
+	// one logs every request.
+	SampleSuccessEvery int
+
+	// SkipPaths are served without an access log entry, e.g. probes hitting
+	// them every few seconds. nil skips the health probe paths, an empty
+	// slice logs everything.
+	SkipPaths []string
+}
+
+// defaultMaxLoggedBodyBytes keeps a logged response body readable and cheap.
+const defaultMaxLoggedBodyBytes = 4 << 10
+
+// LoggerMiddleware writes a structured access log entry for every request.
+func LoggerMiddleware(opts LoggerOptions) func(http.Handler) http.Handler {
+	logger := opts.Logger
+	if logger == nil {
+		logger = slog.Default()
+	}
+
+	maxBody := opts.MaxBodyBytes
+	if maxBody <= 0 {
+		maxBody = defaultMaxLoggedBodyBytes
+	}
+
+	skipPaths := opts.SkipPaths
+	if skipPaths == nil {
+		skipPaths = []string{LivenessPath, ReadinessPath}
+	}
+
+	skip := make(map[string]bool, len(skipPaths))
+	for _, path := range skipPaths {
+		skip[path] = true
+	}
+
+	var successes atomic.Uint64
+
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if skip[r.URL.Path] {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			rec := NewResponseRecorder(w)
+			if opts.LogResponseBody {
+				rec.CaptureBody(maxBody)
+			}
+			r, tracer := withTracer(r)
+
+			start := RequestStartTime(r.Context())
+			if start.IsZero() {
+				start = time.Now()
+			}
+
+			next.ServeHTTP(rec, r)
+
+			duration := time.Since(start)
+
+			attrs := []any{
+				slog.String("method", r.Method),
+				slog.String("path", r.URL.Path),
+				slog.String("route", routePattern(r)),
+				slog.Int("status", rec.Status()),
+				slog.Int("bytes", rec.BytesWritten()),
+				slog.Duration("duration", duration),
+				slog.String("remote_addr", r.RemoteAddr),
+				slog.String("trace_id", TraceID(r.Context())),
+				slog.String("request_id", RequestID(r.Context())),
+			}
+
+			if opts.LogResponseBody {
+				if encoding := rec.BodyEncoding(); encoding != "" {
+					attrs = append(attrs, slog.String("body_encoding", encoding))
+				} else {
+					body, truncated := rec.Body()
+					attrs = append(attrs,
+						slog.String("body", string(body)),
+						slog.Bool("body_truncated", truncated),
+					)
+				}
+			}
+
+			if steps := tracer.Steps(); len(steps) > 0 {
+				names := make([]string, 0, len(steps))
+				for name := range steps {
+					names = append(names, name)
+				}
+				sort.Strings(names)
+
+				stepAttrs := make([]any, 0, len(steps))
+				for _, name := range names {
+					stepAttrs = append(stepAttrs, slog.Duration(name, steps[name]))
+				}
+				attrs = append(attrs, slog.Group("steps", stepAttrs...))
+			}
+
+			if logged(rec.Status(), opts.SampleSuccessEvery, &successes) {
+				logger.InfoContext(r.Context(), "request completed", attrs...)
+			}
+
+			if opts.SlowThreshold > 0 && duration > opts.SlowThreshold {
+				logger.WarnContext(r.Context(), "slow request", append(attrs, slog.Bool("slow", true))...)
+			}
+		})
+	}
+}
+
+// logged reports whether a request with status makes it into the access log.
+// Only one in every `every` 2xx responses is kept.
+func logged(status, every int, successes *atomic.Uint64) bool {
+	if every <= 1 || status < 200 || status >= 300 {
+		return true
+	}
+	return (successes.Add(1)-1)%uint64(every) == 0
+}
+
+// routePattern returns the matched chi pattern, e.g. /users/{id}. It is only
+// complete once routing has run, so read it after calling the next handler.
+func routePattern(r *http.Request) string {
+	rctx := chi.RouteContext(r.Context())
+	if rctx == nil {
+		return ""
+	}
+	return rctx.RoutePattern()
+}
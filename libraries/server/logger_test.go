@@ -0,0 +1,244 @@
+package server
+
+import (
+	"bytes"
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/go-chi/chi"
+)
+
+func TestLoggerMiddlewareResponseBody(t *testing.T) {
+	tests := []struct {
+		name         string
+		contentType  string
+		compress     bool
+		wantBody     string
+		wantEncoding string
+	}{
+		{name: "plain body", contentType: "application/json", wantBody: `{"ok":true}`},
+		{name: "gzipped body", contentType: "application/json", compress: true, wantEncoding: "gzip"},
+		{name: "streaming body", contentType: "application/x-ndjson", wantBody: ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var out bytes.Buffer
+			logger := slog.New(slog.NewJSONHandler(&out, nil))
+
+			var handler http.Handler = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.Header().Set("Content-Type", tt.contentType)
+				w.Write([]byte(`{"ok":true}`))
+			})
+			if tt.compress {
+				handler = CompressMiddleware(CompressDefault)(handler)
+			}
+			handler = LoggerMiddleware(LoggerOptions{Logger: logger, LogResponseBody: true})(handler)
+
+			req := httptest.NewRequest(http.MethodGet, "/items", nil)
+			req.Header.Set("Accept-Encoding", "gzip")
+			handler.ServeHTTP(httptest.NewRecorder(), req)
+
+			var entry map[string]interface{}
+			if err := json.Unmarshal(out.Bytes(), &entry); err != nil {
+				t.Fatalf("decoding log entry %q: %v", out.String(), err)
+			}
+
+			if tt.wantEncoding != "" {
+				if entry["body_encoding"] != tt.wantEncoding {
+					t.Errorf("body_encoding = %v, want %q", entry["body_encoding"], tt.wantEncoding)
+				}
+				if _, ok := entry["body"]; ok {
+					t.Errorf("encoded body logged: %v", entry["body"])
+				}
+				return
+			}
+
+			if entry["body"] != tt.wantBody {
+				t.Errorf("body = %v, want %q", entry["body"], tt.wantBody)
+			}
+		})
+	}
+}
+
+func TestLogged(t *testing.T) {
+	tests := []struct {
+		name   string
+		status int
+		every  int
+		want   []bool
+	}{
+		{name: "no sampling", status: http.StatusOK, every: 0, want: []bool{true, true, true}},
+		{name: "one in two successes", status: http.StatusOK, every: 2, want: []bool{true, false, true, false}},
+		{name: "one in three successes", status: http.StatusCreated, every: 3, want: []bool{true, false, false, true}},
+		{name: "errors always logged", status: http.StatusInternalServerError, every: 2, want: []bool{true, true, true}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var successes atomic.Uint64
+			for i, want := range tt.want {
+				if got := logged(tt.status, tt.every, &successes); got != want {
+					t.Errorf("call %d: logged() = %v, want %v", i, got, want)
+				}
+			}
+		})
+	}
+}
+
+// logEntries decodes every JSON line written to out.
+func logEntries(t *testing.T, out *bytes.Buffer) []map[string]interface{} {
+	t.Helper()
+
+	var entries []map[string]interface{}
+	decoder := json.NewDecoder(out)
+	for decoder.More() {
+		var entry map[string]interface{}
+		if err := decoder.Decode(&entry); err != nil {
+			t.Fatalf("decoding log entry: %v", err)
+		}
+		entries = append(entries, entry)
+	}
+	return entries
+}
+
+func TestLoggerMiddlewareSlowThreshold(t *testing.T) {
+	tests := []struct {
+		name       string
+		delay      time.Duration
+		wantLevels []string
+	}{
+		{name: "fast request", delay: 0, wantLevels: []string{"INFO"}},
+		{name: "slow request", delay: 30 * time.Millisecond, wantLevels: []string{"INFO", "WARN"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var out bytes.Buffer
+			opts := LoggerOptions{
+				Logger:        slog.New(slog.NewJSONHandler(&out, nil)),
+				SlowThreshold: 20 * time.Millisecond,
+			}
+
+			handler := LoggerMiddleware(opts)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				time.Sleep(tt.delay)
+			}))
+			handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/items", nil))
+
+			entries := logEntries(t, &out)
+			if len(entries) != len(tt.wantLevels) {
+				t.Fatalf("got %d entries, want %d", len(entries), len(tt.wantLevels))
+			}
+
+			for i, entry := range entries {
+				if entry["level"] != tt.wantLevels[i] {
+					t.Errorf("entry %d level = %v, want %s", i, entry["level"], tt.wantLevels[i])
+				}
+
+				_, slow := entry["slow"]
+				if wantSlow := tt.wantLevels[i] == "WARN"; slow != wantSlow {
+					t.Errorf("entry %d slow field present = %v, want %v", i, slow, wantSlow)
+				}
+			}
+		})
+	}
+}
+
+func TestLoggerMiddlewareRoutePattern(t *testing.T) {
+	var out bytes.Buffer
+	opts := LoggerOptions{Logger: slog.New(slog.NewJSONHandler(&out, nil))}
+
+	app := chi.NewRouter()
+	app.Use(LoggerMiddleware(opts))
+	app.Get("/users/{id}", func(w http.ResponseWriter, r *http.Request) {})
+
+	app.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/users/123", nil))
+
+	entries := logEntries(t, &out)
+	if len(entries) != 1 {
+		t.Fatalf("got %d entries, want 1", len(entries))
+	}
+	if entries[0]["route"] != "/users/{id}" {
+		t.Errorf("route = %v, want /users/{id}", entries[0]["route"])
+	}
+	if entries[0]["path"] != "/users/123" {
+		t.Errorf("path = %v, want /users/123", entries[0]["path"])
+	}
+}
+
+func TestLoggerMiddlewareSkipPaths(t *testing.T) {
+	tests := []struct {
+		name        string
+		skipPaths   []string
+		path        string
+		wantEntries int
+	}{
+		{name: "liveness skipped by default", path: LivenessPath, wantEntries: 0},
+		{name: "readiness skipped by default", path: ReadinessPath, wantEntries: 0},
+		{name: "other paths logged by default", path: "/items", wantEntries: 1},
+		{name: "custom skip path", skipPaths: []string{"/metrics"}, path: "/metrics", wantEntries: 0},
+		{name: "custom list replaces the default", skipPaths: []string{"/metrics"}, path: LivenessPath, wantEntries: 1},
+		{name: "empty list logs everything", skipPaths: []string{}, path: ReadinessPath, wantEntries: 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var out bytes.Buffer
+			opts := LoggerOptions{
+				Logger:    slog.New(slog.NewJSONHandler(&out, nil)),
+				SkipPaths: tt.skipPaths,
+			}
+
+			handler := LoggerMiddleware(opts)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.Write([]byte("served"))
+			}))
+			rec := httptest.NewRecorder()
+			handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, tt.path, nil))
+
+			if rec.Body.String() != "served" {
+				t.Errorf("body = %q, want the request to be served", rec.Body.String())
+			}
+			if entries := logEntries(t, &out); len(entries) != tt.wantEntries {
+				t.Errorf("got %d entries, want %d", len(entries), tt.wantEntries)
+			}
+		})
+	}
+}
+
+func TestLoggerMiddlewareSampling(t *testing.T) {
+	var out bytes.Buffer
+	opts := LoggerOptions{
+		Logger:             slog.New(slog.NewJSONHandler(&out, nil)),
+		SampleSuccessEvery: 10,
+	}
+
+	handler := LoggerMiddleware(opts)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/fail" {
+			w.WriteHeader(http.StatusInternalServerError)
+		}
+	}))
+
+	for i := 0; i < 100; i++ {
+		handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/items", nil))
+		if i%20 == 0 {
+			handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/fail", nil))
+		}
+	}
+
+	counts := make(map[float64]int)
+	for _, entry := range logEntries(t, &out) {
+		counts[entry["status"].(float64)]++
+	}
+
+	if got := counts[http.StatusOK]; got != 10 {
+		t.Errorf("logged %d of 100 successes, want 10", got)
+	}
+	if got := counts[http.StatusInternalServerError]; got != 5 {
+		t.Errorf("logged %d of 5 errors, want 5", got)
+	}
+}
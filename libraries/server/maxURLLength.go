@@ -0,0 +1,21 @@
+package server
+
+import (
+	"net/http"
+
+	"github.com/himtar/go-boilerplate/pkg/errors"
+)
+
+// MaxURLLengthMiddleware rejects requests whose path plus query exceeds maxBytes with a 414.
+func MaxURLLengthMiddleware(maxBytes int) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if len(r.URL.RequestURI()) > maxBytes {
+				errors.URITooLong(w)
+				return
+			}
+
+			next.ServeHTTP(w, r)
+		})
+	}
+}
@@ -0,0 +1,36 @@
+package server
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestMaxURLLengthMiddleware(t *testing.T) {
+	const limit = 32
+
+	tests := []struct {
+		name       string
+		target     string
+		wantStatus int
+	}{
+		{name: "just under the limit", target: "/search?q=" + strings.Repeat("a", limit-11), wantStatus: http.StatusOK},
+		{name: "at the limit", target: "/search?q=" + strings.Repeat("a", limit-10), wantStatus: http.StatusOK},
+		{name: "just over the limit", target: "/search?q=" + strings.Repeat("a", limit-9), wantStatus: http.StatusRequestURITooLong},
+		{name: "long path", target: "/" + strings.Repeat("p", limit), wantStatus: http.StatusRequestURITooLong},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			handler := MaxURLLengthMiddleware(limit)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+
+			rec := httptest.NewRecorder()
+			handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, tt.target, nil))
+
+			if rec.Code != tt.wantStatus {
+				t.Errorf("status for %d byte URL = %d, want %d", len(tt.target), rec.Code, tt.wantStatus)
+			}
+		})
+	}
+}
@@ -0,0 +1,77 @@
+package server
+
+import (
+	"encoding/json"
+	"net/http"
+	"reflect"
+	"sort"
+	"testing"
+
+	"github.com/go-chi/chi"
+)
+
+func TestGenerateOpenAPI(t *testing.T) {
+	noop := func(w http.ResponseWriter, r *http.Request) {}
+
+	r, err := NewHTTPRouter(chi.NewRouter(), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	r.Get("/users", noop)
+	r.Method(http.MethodPost, "/users", WithSummary("Create a user", noop))
+	r.Route("/users/{id:[0-9]+}", func(r *HTTPRouter) {
+		r.Get("/", noop)
+		r.Delete("/", noop)
+	})
+
+	data, err := GenerateOpenAPI(r, "Users API", "1.0.0")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var spec openAPISpec
+	if err := json.Unmarshal(data, &spec); err != nil {
+		t.Fatalf("invalid spec: %v", err)
+	}
+
+	tests := []struct {
+		path        string
+		wantMethods []string
+	}{
+		{path: "/users", wantMethods: []string{"get", "post"}},
+		{path: "/users/{id}", wantMethods: []string{"delete", "get"}},
+	}
+
+	if len(spec.Paths) != len(tests) {
+		t.Errorf("spec lists %d paths, want %d: %v", len(spec.Paths), len(tests), spec.Paths)
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			ops, ok := spec.Paths[tt.path]
+			if !ok {
+				t.Fatalf("path %s missing", tt.path)
+			}
+
+			var methods []string
+			for method := range ops {
+				methods = append(methods, method)
+			}
+			sort.Strings(methods)
+
+			if !reflect.DeepEqual(methods, tt.wantMethods) {
+				t.Errorf("methods = %v, want %v", methods, tt.wantMethods)
+			}
+		})
+	}
+
+	if got := spec.Paths["/users"]["post"].Summary; got != "Create a user" {
+		t.Errorf("summary = %q, want %q", got, "Create a user")
+	}
+
+	params := spec.Paths["/users/{id}"]["get"].Parameters
+	if len(params) != 1 || params[0].Name != "id" || params[0].In != "path" {
+		t.Errorf("parameters = %+v, want the id path parameter", params)
+	}
+}
@@ -0,0 +1,27 @@
+package server
+
+import (
+	"net/http"
+	"strings"
+)
+
+// PathScoped applies mw only to requests under prefix, e.g. auth on /api but
+// not on /health. prefix matches whole path segments, so /api covers /api and
+// /api/users but not /apis. Other requests skip mw entirely.
+func PathScoped(prefix string, mw func(http.Handler) http.Handler) func(http.Handler) http.Handler {
+	prefix = strings.TrimSuffix(prefix, "/")
+
+	return func(next http.Handler) http.Handler {
+		scoped := mw(next)
+
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			path := r.URL.Path
+			if path == prefix || strings.HasPrefix(path, prefix+"/") {
+				scoped.ServeHTTP(w, r)
+				return
+			}
+
+			next.ServeHTTP(w, r)
+		})
+	}
+}
@@ -0,0 +1,46 @@
+package server
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestPathScoped(t *testing.T) {
+	tests := []struct {
+		name    string
+		prefix  string
+		path    string
+		wantRun bool
+	}{
+		{name: "prefix itself", prefix: "/api", path: "/api", wantRun: true},
+		{name: "below prefix", prefix: "/api", path: "/api/users", wantRun: true},
+		{name: "trailing slash prefix", prefix: "/api/", path: "/api/users", wantRun: true},
+		{name: "other path", prefix: "/api", path: "/health"},
+		{name: "partial segment", prefix: "/api", path: "/apis"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var ran, reached bool
+			mw := func(next http.Handler) http.Handler {
+				return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+					ran = true
+					next.ServeHTTP(w, r)
+				})
+			}
+
+			handler := PathScoped(tt.prefix, mw)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				reached = true
+			}))
+			handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, tt.path, nil))
+
+			if ran != tt.wantRun {
+				t.Errorf("middleware ran = %v, want %v", ran, tt.wantRun)
+			}
+			if !reached {
+				t.Error("handler was not reached")
+			}
+		})
+	}
+}
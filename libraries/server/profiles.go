@@ -0,0 +1,28 @@
+package server
+
+import (
+	"net/http"
+	"strings"
+)
+
+// isDevelopment reports whether env names the development profile.
+func isDevelopment(env string) bool {
+	switch strings.ToLower(env) {
+	case "development", "dev":
+		return true
+	}
+	return false
+}
+
+// EnvMiddlewares returns the middleware added on top of the base stack for
+// the given environment. Production compresses responses, development keeps
+// them plain so they are easy to inspect.
+func EnvMiddlewares(env string) []func(http.Handler) http.Handler {
+	if isDevelopment(env) {
+		return nil
+	}
+
+	return []func(http.Handler) http.Handler{
+		CompressMiddleware(CompressDefault),
+	}
+}
@@ -0,0 +1,48 @@
+package server
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+
+	"github.com/go-chi/chi"
+)
+
+func TestVariablesProfiling(t *testing.T) {
+	tests := []struct {
+		name string
+		env  Variables
+		want bool
+	}{
+		{name: "explicit development", env: Variables{env: "development", envSet: true}, want: true},
+		{name: "explicit dev", env: Variables{env: "DEV", envSet: true}, want: true},
+		{name: "defaulted development", env: Variables{env: "development"}, want: false},
+		{name: "explicit production", env: Variables{env: "production", envSet: true}, want: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.env.Profiling(); got != tt.want {
+				t.Errorf("Profiling() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPrepareServerMountsProfilerOnlyWhenProfiling(t *testing.T) {
+	for _, profiling := range []bool{false, true} {
+		var served atomic.Uint64
+		handler, err := prepareServer(chi.NewRouter(), &served, profiling, nil, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/debug/pprof/", nil))
+
+		if got := rec.Code == http.StatusOK; got != profiling {
+			t.Errorf("profiling=%v: /debug/pprof/ status = %d", profiling, rec.Code)
+		}
+	}
+}
@@ -0,0 +1,38 @@
+package server
+
+import (
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/go-chi/chi"
+	"github.com/himtar/go-boilerplate/pkg/router"
+)
+
+// Proxy forwards every request under pattern to targetURL, e.g. with pattern
+// "/users" and target "http://users:8080/v1" a request for /users/42 goes to
+// http://users:8080/v1/42. Trace headers are passed on and responses are
+// streamed back as they arrive, see router.NewProxy.
+func (r *HTTPRouter) Proxy(pattern, targetURL string) error {
+	proxy, err := router.NewProxy(targetURL, func(req *http.Request) string {
+		rest := chi.URLParam(req, "*")
+		// chi matches on the raw path when there is one, the proxy wants it decoded
+		if req.URL.RawPath != "" {
+			if unescaped, err := url.PathUnescape(rest); err == nil {
+				rest = unescaped
+			}
+		}
+		return "/" + rest
+	})
+	if err != nil {
+		return err
+	}
+
+	prefix := strings.TrimSuffix(pattern, "/")
+	if prefix != "" {
+		r.Handle(prefix, proxy)
+	}
+	r.Handle(prefix+"/*", proxy)
+
+	return nil
+}
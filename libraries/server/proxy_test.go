@@ -0,0 +1,67 @@
+package server
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/go-chi/chi"
+)
+
+func TestHTTPRouterProxy(t *testing.T) {
+	var gotPath, gotTrace string
+	upstream := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotPath, gotTrace = r.URL.Path, r.Header.Get(TraceIDHeader)
+		w.WriteHeader(http.StatusAccepted)
+	}))
+	defer upstream.Close()
+
+	tests := []struct {
+		name     string
+		path     string
+		wantPath string
+	}{
+		{name: "nested path", path: "/api/users/42/orders", wantPath: "/v1/42/orders"},
+		{name: "prefix only", path: "/api/users", wantPath: "/v1/"},
+		{name: "escaped segment", path: "/api/users/a%2Fb", wantPath: "/v1/a/b"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r, err := NewHTTPRouter(chi.NewRouter(), nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+			r.Use(TraceIDMiddleware)
+			r.Route("/api", func(r *HTTPRouter) {
+				if err := r.Proxy("/users/", upstream.URL+"/v1"); err != nil {
+					t.Fatal(err)
+				}
+			})
+
+			rec := httptest.NewRecorder()
+			r.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, tt.path, nil))
+
+			if rec.Code != http.StatusAccepted {
+				t.Fatalf("status = %d, want %d", rec.Code, http.StatusAccepted)
+			}
+			if gotPath != tt.wantPath {
+				t.Errorf("upstream path = %q, want %q", gotPath, tt.wantPath)
+			}
+			if want := rec.Header().Get(TraceIDHeader); gotTrace == "" || gotTrace != want {
+				t.Errorf("upstream trace ID = %q, want %q", gotTrace, want)
+			}
+		})
+	}
+}
+
+func TestHTTPRouterProxyInvalidTarget(t *testing.T) {
+	r, err := NewHTTPRouter(chi.NewRouter(), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := r.Proxy("/users", "http://[::1"); err == nil {
+		t.Error("invalid target URL accepted")
+	}
+}
@@ -0,0 +1,187 @@
+package server
+
+import (
+	"io"
+	"log/slog"
+	"math"
+	"net"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/himtar/go-boilerplate/pkg/response"
+)
+
+// pruneBucketsAbove bounds how many client buckets are kept before full ones are dropped.
+const pruneBucketsAbove = 10000
+
+type tokenBucket struct {
+	tokens float64
+	last   time.Time
+}
+
+// TokenBucketLimiter keeps a token bucket per client key.
+type TokenBucketLimiter struct {
+	mu       sync.Mutex
+	capacity float64
+	rate     float64
+	buckets  map[string]*tokenBucket
+}
+
+// NewTokenBucketLimiter creates a new instance of TokenBucketLimiter whose
+// buckets hold capacity tokens and refill at refillPerSecond.
+func NewTokenBucketLimiter(capacity, refillPerSecond float64) *TokenBucketLimiter {
+	return &TokenBucketLimiter{
+		capacity: capacity,
+		rate:     refillPerSecond,
+		buckets:  make(map[string]*tokenBucket),
+	}
+}
+
+// Take removes cost tokens from key's bucket. When there aren't enough it
+// takes nothing and reports how long until there will be.
+func (l *TokenBucketLimiter) Take(key string, cost float64) (ok bool, remaining float64, retryAfter time.Duration) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	now := time.Now()
+	if len(l.buckets) > pruneBucketsAbove {
+		l.prune(now)
+	}
+
+	bucket, found := l.buckets[key]
+	if !found {
+		bucket = &tokenBucket{tokens: l.capacity, last: now}
+		l.buckets[key] = bucket
+	}
+
+	bucket.tokens = math.Min(l.capacity, bucket.tokens+now.Sub(bucket.last).Seconds()*l.rate)
+	bucket.last = now
+
+	if bucket.tokens < cost {
+		wait := time.Duration((cost - bucket.tokens) / l.rate * float64(time.Second))
+		return false, bucket.tokens, wait
+	}
+
+	bucket.tokens -= cost
+	return true, bucket.tokens, 0
+}
+
+// prune drops buckets that have refilled completely, they hold no state worth keeping.
+func (l *TokenBucketLimiter) prune(now time.Time) {
+	for key, bucket := range l.buckets {
+		if bucket.tokens+now.Sub(bucket.last).Seconds()*l.rate >= l.capacity {
+			delete(l.buckets, key)
+		}
+	}
+}
+
+// Charge removes cost tokens from key's bucket even when there aren't enough,
+// the debt delays the client's next requests. It prices work only known after
+// the fact, e.g. bytes read from a chunked body.
+func (l *TokenBucketLimiter) Charge(key string, cost float64) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	now := time.Now()
+	bucket, found := l.buckets[key]
+	if !found {
+		bucket = &tokenBucket{tokens: l.capacity, last: now}
+		l.buckets[key] = bucket
+	}
+
+	bucket.tokens = math.Min(l.capacity, bucket.tokens+now.Sub(bucket.last).Seconds()*l.rate) - cost
+	bucket.last = now
+}
+
+// meteredBody counts the bytes read past the declared Content-Length, so
+// CostRateLimitMiddleware can charge for bodies of unknown or wrong length.
+type meteredBody struct {
+	io.ReadCloser
+	bytesPerToken int64
+	declared      int64
+	read          int64
+}
+
+func (b *meteredBody) Read(p []byte) (int, error) {
+	n, err := b.ReadCloser.Read(p)
+	b.read += int64(n)
+	return n, err
+}
+
+// extraCost prices the bytes read beyond what was charged upfront.
+func (b *meteredBody) extraCost() float64 {
+	if b.read <= b.declared {
+		return 0
+	}
+	return float64(b.read-b.declared) / float64(b.bytesPerToken)
+}
+
+// CostByBodySize charges one token per request plus one per bytesPerToken of
+// body. The declared Content-Length is charged upfront, bytes read past it,
+// e.g. of chunked bodies, once the handler is done. A bytesPerToken that isn't
+// positive can't price bodies, requests then cost one token whatever their size.
+func CostByBodySize(bytesPerToken int64) func(r *http.Request) float64 {
+	if bytesPerToken <= 0 {
+		slog.Warn("rate limit bytesPerToken must be positive, charging one token per request",
+			slog.Int64("bytes_per_token", bytesPerToken),
+		)
+		return func(r *http.Request) float64 { return 1 }
+	}
+
+	return func(r *http.Request) float64 {
+		declared := r.ContentLength
+		if declared < 0 {
+			declared = 0
+		}
+
+		if r.Body != nil && r.Body != http.NoBody {
+			r.Body = &meteredBody{ReadCloser: r.Body, bytesPerToken: bytesPerToken, declared: declared}
+		}
+
+		return 1 + float64(declared)/float64(bytesPerToken)
+	}
+}
+
+// CostRateLimitMiddleware rate limits clients, keyed by IP, by the cost of
+// their requests rather than their count, so a few large uploads can't
+// starve the service. costFn prices a request, e.g. CostByBodySize. Requests
+// costing more than a full bucket can never pass and get a 413.
+func CostRateLimitMiddleware(limiter *TokenBucketLimiter, costFn func(r *http.Request) float64) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			cost := costFn(r)
+			if cost > limiter.capacity {
+				response.Send(w, http.StatusRequestEntityTooLarge, "Request too large", nil)
+				return
+			}
+
+			key := clientIP(r)
+			ok, remaining, retryAfter := limiter.Take(key, cost)
+			if !ok {
+				// a bucket in debt after Charge still reports nothing left, not a negative count
+				response.SendRateLimited(w, int(limiter.capacity), int(math.Max(0, remaining)), time.Now().Add(retryAfter))
+				return
+			}
+
+			metered, _ := r.Body.(*meteredBody)
+			next.ServeHTTP(w, r)
+
+			if metered != nil {
+				if extra := metered.extraCost(); extra > 0 {
+					limiter.Charge(key, extra)
+				}
+			}
+		})
+	}
+}
+
+// clientIP returns the host part of RemoteAddr, already rewritten by RealIP
+// when TRUST_PROXY is set.
+func clientIP(r *http.Request) string {
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		return r.RemoteAddr
+	}
+	return host
+}
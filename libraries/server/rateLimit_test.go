@@ -0,0 +1,123 @@
+package server
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestCostRateLimitMiddlewareByBodySize(t *testing.T) {
+	tests := []struct {
+		name          string
+		body          string
+		chunked       bool
+		wantStatus    int
+		wantRemaining float64
+	}{
+		{name: "empty body costs one token", wantStatus: http.StatusOK, wantRemaining: 9},
+		{name: "small body", body: strings.Repeat("x", 100), wantStatus: http.StatusOK, wantRemaining: 8},
+		{name: "large body", body: strings.Repeat("x", 500), wantStatus: http.StatusOK, wantRemaining: 4},
+		{name: "chunked body is charged once read", body: strings.Repeat("x", 500), chunked: true, wantStatus: http.StatusOK, wantRemaining: 4},
+		{name: "body above capacity", body: strings.Repeat("x", 1000), wantStatus: http.StatusRequestEntityTooLarge, wantRemaining: 10},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// refills a token a day, so no refill during the test
+			limiter := NewTokenBucketLimiter(10, 1.0/86400)
+			handler := CostRateLimitMiddleware(limiter, CostByBodySize(100))(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				io.Copy(io.Discard, r.Body)
+			}))
+
+			req := httptest.NewRequest(http.MethodPost, "/upload", strings.NewReader(tt.body))
+			if tt.chunked {
+				req.ContentLength = -1
+			}
+			rec := httptest.NewRecorder()
+			handler.ServeHTTP(rec, req)
+
+			if rec.Code != tt.wantStatus {
+				t.Fatalf("status = %d, want %d", rec.Code, tt.wantStatus)
+			}
+
+			_, remaining, _ := limiter.Take(clientIP(req), 0)
+			if remaining < tt.wantRemaining-0.01 || remaining > tt.wantRemaining+0.01 {
+				t.Errorf("remaining tokens = %.2f, want %.2f", remaining, tt.wantRemaining)
+			}
+		})
+	}
+}
+
+func TestCostRateLimitMiddlewareRejectsWhenEmpty(t *testing.T) {
+	limiter := NewTokenBucketLimiter(2, 1.0/86400)
+	handler := CostRateLimitMiddleware(limiter, func(*http.Request) float64 { return 1 })(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+
+	want := []int{http.StatusOK, http.StatusOK, http.StatusTooManyRequests}
+	for i, status := range want {
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/", nil))
+		if rec.Code != status {
+			t.Errorf("request %d status = %d, want %d", i, rec.Code, status)
+		}
+	}
+}
+
+func TestCostByBodySizeInvalidRate(t *testing.T) {
+	for _, bytesPerToken := range []int64{0, -1} {
+		cost := CostByBodySize(bytesPerToken)
+
+		req := httptest.NewRequest(http.MethodPost, "/upload", strings.NewReader(strings.Repeat("x", 1000)))
+		if got := cost(req); got != 1 {
+			t.Errorf("CostByBodySize(%d) cost = %v, want 1", bytesPerToken, got)
+		}
+	}
+}
+
+func TestCostRateLimitMiddlewareHeaders(t *testing.T) {
+	limiter := NewTokenBucketLimiter(1, 0.5)
+	handler := CostRateLimitMiddleware(limiter, func(*http.Request) float64 { return 1 })(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+
+	handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/", nil))
+
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/", nil))
+
+	if rec.Code != http.StatusTooManyRequests {
+		t.Fatalf("status = %d, want %d", rec.Code, http.StatusTooManyRequests)
+	}
+
+	want := map[string]string{
+		"X-RateLimit-Limit":     "1",
+		"X-RateLimit-Remaining": "0",
+		"Retry-After":           "2",
+	}
+	for name, value := range want {
+		if got := rec.Header().Get(name); got != value {
+			t.Errorf("%s = %q, want %q", name, got, value)
+		}
+	}
+	if rec.Header().Get("X-RateLimit-Reset") == "" {
+		t.Error("X-RateLimit-Reset missing")
+	}
+}
+
+func TestCostRateLimitMiddlewareRemainingNeverNegative(t *testing.T) {
+	limiter := NewTokenBucketLimiter(2, 1.0/86400)
+	handler := CostRateLimitMiddleware(limiter, func(*http.Request) float64 { return 1 })(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	// a chunked body read after the fact put the bucket 3 tokens into debt
+	limiter.Charge(clientIP(req), 5)
+
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusTooManyRequests {
+		t.Fatalf("status = %d, want %d", rec.Code, http.StatusTooManyRequests)
+	}
+	if got := rec.Header().Get("X-RateLimit-Remaining"); got != "0" {
+		t.Errorf("X-RateLimit-Remaining = %q, want 0", got)
+	}
+}
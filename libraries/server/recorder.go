@@ -0,0 +1,158 @@
+package server
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"net"
+	"net/http"
+	"strings"
+)
+
+// ResponseRecorder wraps a ResponseWriter recording the status and size of
+// the response. Middleware should get one through NewResponseRecorder so a
+// chain shares a single wrapper instead of wrapping again and again.
+type ResponseRecorder struct {
+	http.ResponseWriter
+	status  int
+	bytes   int
+	written bool
+
+	// body capture, only enabled through CaptureBody
+	captureLimit int
+	body         bytes.Buffer
+	truncated    bool
+	streaming    bool
+	encoding     string
+}
+
+// streamingContentTypes are never captured, they may be endless or huge.
+var streamingContentTypes = []string{
+	"text/event-stream",
+	"application/x-ndjson",
+	"application/octet-stream",
+	"audio/",
+	"video/",
+}
+
+// NewResponseRecorder returns w itself when it already is a ResponseRecorder
+// and wraps it otherwise.
+func NewResponseRecorder(w http.ResponseWriter) *ResponseRecorder {
+	if rec, ok := w.(*ResponseRecorder); ok {
+		return rec
+	}
+	return &ResponseRecorder{ResponseWriter: w}
+}
+
+func (rec *ResponseRecorder) WriteHeader(status int) {
+	if rec.written {
+		return
+	}
+	rec.status = status
+	rec.written = true
+	rec.ResponseWriter.WriteHeader(status)
+}
+
+func (rec *ResponseRecorder) Write(b []byte) (int, error) {
+	if !rec.written {
+		rec.WriteHeader(http.StatusOK)
+	}
+
+	n, err := rec.ResponseWriter.Write(b)
+	rec.bytes += n
+	rec.capture(b[:n])
+	return n, err
+}
+
+// CaptureBody makes the recorder keep a copy of at most limit body bytes.
+// Streaming content types and encoded, e.g. gzipped, bodies are skipped
+// altogether.
+func (rec *ResponseRecorder) CaptureBody(limit int) {
+	rec.captureLimit = limit
+}
+
+func (rec *ResponseRecorder) capture(b []byte) {
+	if rec.captureLimit <= 0 || rec.streaming {
+		return
+	}
+
+	if rec.body.Len() == 0 && !rec.truncated {
+		// the logger sits outside Compress, the bytes would be unreadable
+		if encoding := rec.Header().Get("Content-Encoding"); encoding != "" {
+			rec.encoding = encoding
+			rec.streaming = true
+			return
+		}
+
+		contentType := rec.Header().Get("Content-Type")
+		for _, prefix := range streamingContentTypes {
+			if strings.HasPrefix(contentType, prefix) {
+				rec.streaming = true
+				return
+			}
+		}
+	}
+
+	room := rec.captureLimit - rec.body.Len()
+	if len(b) > room {
+		b, rec.truncated = b[:room], true
+	}
+	rec.body.Write(b)
+}
+
+// Body returns the captured body and whether it was cut at the capture limit.
+// It is empty unless CaptureBody was called or for streaming and encoded
+// responses.
+func (rec *ResponseRecorder) Body() ([]byte, bool) {
+	return rec.body.Bytes(), rec.truncated
+}
+
+// BodyEncoding returns the Content-Encoding that kept the body from being
+// captured, "" when it wasn't encoded.
+func (rec *ResponseRecorder) BodyEncoding() string {
+	return rec.encoding
+}
+
+// Status returns the status code sent, 200 if the handler wrote nothing.
+func (rec *ResponseRecorder) Status() int {
+	if rec.status == 0 {
+		return http.StatusOK
+	}
+	return rec.status
+}
+
+// BytesWritten returns the number of body bytes written.
+func (rec *ResponseRecorder) BytesWritten() int {
+	return rec.bytes
+}
+
+// Written reports whether the header has been sent.
+func (rec *ResponseRecorder) Written() bool {
+	return rec.written
+}
+
+func (rec *ResponseRecorder) Flush() {
+	_ = rec.FlushError()
+}
+
+// FlushError flushes like Flush but reports failures, e.g. the client being
+// gone. http.ResponseController uses it.
+func (rec *ResponseRecorder) FlushError() error {
+	if !rec.written {
+		rec.WriteHeader(http.StatusOK)
+	}
+	return http.NewResponseController(rec.ResponseWriter).Flush()
+}
+
+func (rec *ResponseRecorder) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	h, ok := rec.ResponseWriter.(http.Hijacker)
+	if !ok {
+		return nil, nil, errors.New("underlying ResponseWriter does not support hijacking")
+	}
+	return h.Hijack()
+}
+
+// Unwrap lets http.ResponseController reach the underlying writer.
+func (rec *ResponseRecorder) Unwrap() http.ResponseWriter {
+	return rec.ResponseWriter
+}
@@ -0,0 +1,152 @@
+package server
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"syscall"
+	"testing"
+)
+
+func TestResponseRecorderThroughChain(t *testing.T) {
+	tests := []struct {
+		name        string
+		handler     http.HandlerFunc
+		wantStatus  int
+		wantBytes   int
+		wantWritten bool
+	}{
+		{
+			name: "explicit status",
+			handler: func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(http.StatusTeapot)
+				w.Write([]byte("short"))
+			},
+			wantStatus:  http.StatusTeapot,
+			wantBytes:   5,
+			wantWritten: true,
+		},
+		{
+			name:        "implicit 200 on write",
+			handler:     func(w http.ResponseWriter, r *http.Request) { w.Write([]byte("hello")) },
+			wantStatus:  http.StatusOK,
+			wantBytes:   5,
+			wantWritten: true,
+		},
+		{
+			name: "first status wins",
+			handler: func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(http.StatusAccepted)
+				w.WriteHeader(http.StatusInternalServerError)
+			},
+			wantStatus:  http.StatusAccepted,
+			wantWritten: true,
+		},
+		{
+			name:       "nothing written",
+			handler:    func(w http.ResponseWriter, r *http.Request) {},
+			wantStatus: http.StatusOK,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var recorders []*ResponseRecorder
+			wrap := func(next http.Handler) http.Handler {
+				return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+					rec := NewResponseRecorder(w)
+					recorders = append(recorders, rec)
+					next.ServeHTTP(rec, r)
+				})
+			}
+
+			// three middleware wrapping in turn, e.g. logger, metrics and recoverer
+			handler := wrap(wrap(wrap(tt.handler)))
+
+			rec := httptest.NewRecorder()
+			handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/", nil))
+
+			for i, r := range recorders {
+				if r != recorders[0] {
+					t.Fatalf("middleware %d wrapped the writer again", i)
+				}
+			}
+
+			got := recorders[0]
+			if got.Status() != tt.wantStatus {
+				t.Errorf("Status() = %d, want %d", got.Status(), tt.wantStatus)
+			}
+			if got.BytesWritten() != tt.wantBytes {
+				t.Errorf("BytesWritten() = %d, want %d", got.BytesWritten(), tt.wantBytes)
+			}
+			if got.Written() != tt.wantWritten {
+				t.Errorf("Written() = %v, want %v", got.Written(), tt.wantWritten)
+			}
+			if tt.wantWritten && rec.Code != tt.wantStatus {
+				t.Errorf("client status = %d, want %d", rec.Code, tt.wantStatus)
+			}
+		})
+	}
+}
+
+func TestResponseRecorderCaptureLimit(t *testing.T) {
+	rec := NewResponseRecorder(httptest.NewRecorder())
+	rec.CaptureBody(8)
+
+	rec.Write([]byte("hello "))
+	rec.Write([]byte("world"))
+
+	body, truncated := rec.Body()
+	if string(body) != "hello wo" || !truncated {
+		t.Errorf("Body() = %q, %v, want %q, true", body, truncated, "hello wo")
+	}
+	if rec.BytesWritten() != 11 {
+		t.Errorf("BytesWritten() = %d, want 11", rec.BytesWritten())
+	}
+}
+
+func TestResponseRecorderFlush(t *testing.T) {
+	inner := httptest.NewRecorder()
+	rec := NewResponseRecorder(inner)
+
+	if err := http.NewResponseController(rec).Flush(); err != nil {
+		t.Fatal(err)
+	}
+	if !inner.Flushed || rec.Status() != http.StatusOK || !rec.Written() {
+		t.Errorf("flush did not send the header through the recorder")
+	}
+}
+
+// failingFlushWriter reports a dropped connection on every flush.
+type failingFlushWriter struct {
+	*httptest.ResponseRecorder
+}
+
+func (f failingFlushWriter) FlushError() error {
+	return syscall.ECONNRESET
+}
+
+func TestWritersReportFlushErrors(t *testing.T) {
+	tests := []struct {
+		name string
+		wrap func(w http.ResponseWriter) http.ResponseWriter
+	}{
+		{name: "recorder", wrap: func(w http.ResponseWriter) http.ResponseWriter { return NewResponseRecorder(w) }},
+		{name: "compress", wrap: func(w http.ResponseWriter) http.ResponseWriter {
+			return &compressWriter{ResponseWriter: w, level: CompressDefault}
+		}},
+		{name: "cacheable", wrap: func(w http.ResponseWriter) http.ResponseWriter {
+			return &cacheWriter{ResponseWriter: w, safe: true}
+		}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := tt.wrap(failingFlushWriter{httptest.NewRecorder()})
+
+			if err := http.NewResponseController(w).Flush(); !errors.Is(err, syscall.ECONNRESET) {
+				t.Errorf("Flush() = %v, want the underlying flush error", err)
+			}
+		})
+	}
+}
@@ -0,0 +1,81 @@
+package server
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"runtime/debug"
+	"syscall"
+
+	"github.com/himtar/go-boilerplate/pkg/response"
+)
+
+// RecovererMiddleware turns a panicking handler into a 500 JSON response and
+// logs the panic with the request's trace and request IDs, method, path,
+// panic value and stack. slog.Default() is used when logger is nil.
+//
+// http.ErrAbortHandler is re-panicked so net/http aborts the response as
+// intended, and panics caused by the client going away (broken pipe,
+// connection reset) are only logged at debug since nobody is left to answer.
+func RecovererMiddleware(logger *slog.Logger) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			rec := NewResponseRecorder(w)
+
+			defer func() {
+				value := recover()
+				if value == nil {
+					return
+				}
+
+				if value == http.ErrAbortHandler {
+					panic(value)
+				}
+
+				l := logger
+				if l == nil {
+					l = slog.Default()
+				}
+
+				if isClientDisconnect(value) {
+					l.DebugContext(r.Context(), "client disconnected",
+						slog.String("trace_id", TraceID(r.Context())),
+						slog.String("request_id", RequestID(r.Context())),
+						slog.String("method", r.Method),
+						slog.String("path", r.URL.Path),
+						slog.String("value", fmt.Sprint(value)),
+					)
+					return
+				}
+
+				l.ErrorContext(r.Context(), "panic recovered",
+					slog.String("trace_id", TraceID(r.Context())),
+					slog.String("request_id", RequestID(r.Context())),
+					slog.String("method", r.Method),
+					slog.String("path", r.URL.Path),
+					slog.String("value", fmt.Sprint(value)),
+					slog.String("stack", string(debug.Stack())),
+				)
+
+				// too late for a clean error once the handler started responding
+				if !rec.Written() {
+					response.Send(rec, http.StatusInternalServerError, http.StatusText(http.StatusInternalServerError), nil)
+				}
+			}()
+
+			next.ServeHTTP(rec, r)
+		})
+	}
+}
+
+// isClientDisconnect reports whether a panic value is a write error caused by
+// the client closing the connection.
+func isClientDisconnect(value interface{}) bool {
+	err, ok := value.(error)
+	if !ok {
+		return false
+	}
+
+	return errors.Is(err, syscall.EPIPE) || errors.Is(err, syscall.ECONNRESET)
+}
@@ -0,0 +1,174 @@
+package server
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"syscall"
+	"testing"
+)
+
+type paymentError struct {
+	OrderID int
+}
+
+func (e paymentError) String() string {
+	return fmt.Sprintf("payment failed for order %d", e.OrderID)
+}
+
+func TestRecovererMiddlewareLogsRequestContext(t *testing.T) {
+	var out bytes.Buffer
+	logger := slog.New(slog.NewJSONHandler(&out, nil))
+
+	handler := NewTraceIDMiddleware(sequence("trace"))(
+		NewRequestIDMiddleware(sequence("req"))(
+			RecovererMiddleware(logger)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				panic(paymentError{OrderID: 42})
+			})),
+		),
+	)
+
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/orders/42/pay", nil))
+
+	if rec.Code != http.StatusInternalServerError {
+		t.Errorf("status = %d, want %d", rec.Code, http.StatusInternalServerError)
+	}
+
+	entries := logEntries(t, &out)
+	if len(entries) != 1 {
+		t.Fatalf("got %d entries, want 1", len(entries))
+	}
+	entry := entries[0]
+
+	want := map[string]string{
+		"level":      "ERROR",
+		"msg":        "panic recovered",
+		"trace_id":   "trace-1",
+		"request_id": "req-1",
+		"method":     http.MethodPost,
+		"path":       "/orders/42/pay",
+		"value":      "payment failed for order 42",
+	}
+	for key, value := range want {
+		if entry[key] != value {
+			t.Errorf("%s = %v, want %q", key, entry[key], value)
+		}
+	}
+
+	if stack, _ := entry["stack"].(string); !strings.Contains(stack, "recoverer_test.go") {
+		t.Errorf("stack does not reach the panicking handler: %q", stack)
+	}
+}
+
+func TestRecovererMiddleware(t *testing.T) {
+	tests := []struct {
+		name       string
+		handler    http.HandlerFunc
+		wantStatus int
+		wantLevel  string
+	}{
+		{
+			name:       "panic before writing",
+			handler:    func(w http.ResponseWriter, r *http.Request) { panic("boom") },
+			wantStatus: http.StatusInternalServerError,
+			wantLevel:  "ERROR",
+		},
+		{
+			name: "panic after writing keeps the status",
+			handler: func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(http.StatusAccepted)
+				panic("boom")
+			},
+			wantStatus: http.StatusAccepted,
+			wantLevel:  "ERROR",
+		},
+		{
+			name:       "client gone",
+			handler:    func(w http.ResponseWriter, r *http.Request) { panic(fmt.Errorf("write: %w", syscall.EPIPE)) },
+			wantStatus: http.StatusOK,
+			wantLevel:  "DEBUG",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var out bytes.Buffer
+			logger := slog.New(slog.NewJSONHandler(&out, &slog.HandlerOptions{Level: slog.LevelDebug}))
+
+			rec := httptest.NewRecorder()
+			RecovererMiddleware(logger)(tt.handler).ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/", nil))
+
+			if rec.Code != tt.wantStatus {
+				t.Errorf("status = %d, want %d", rec.Code, tt.wantStatus)
+			}
+
+			var entry map[string]interface{}
+			if err := json.Unmarshal(out.Bytes(), &entry); err != nil {
+				t.Fatalf("decoding log entry %q: %v", out.String(), err)
+			}
+			if entry["level"] != tt.wantLevel {
+				t.Errorf("level = %v, want %s", entry["level"], tt.wantLevel)
+			}
+		})
+	}
+}
+
+func TestRecovererMiddlewareRepanicsAbort(t *testing.T) {
+	handler := RecovererMiddleware(nil)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		panic(http.ErrAbortHandler)
+	}))
+
+	defer func() {
+		if value := recover(); value != http.ErrAbortHandler {
+			t.Errorf("recovered %v, want http.ErrAbortHandler", value)
+		}
+	}()
+	handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/", nil))
+}
+
+func TestRecovererMiddlewareClientAbortsAreNotErrors(t *testing.T) {
+	tests := []struct {
+		name        string
+		value       interface{}
+		wantRepanic bool
+	}{
+		{name: "abort handler", value: http.ErrAbortHandler, wantRepanic: true},
+		{name: "broken pipe", value: fmt.Errorf("write tcp: %w", syscall.EPIPE)},
+		{name: "connection reset", value: fmt.Errorf("write tcp: %w", syscall.ECONNRESET)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var out bytes.Buffer
+			logger := slog.New(slog.NewJSONHandler(&out, &slog.HandlerOptions{Level: slog.LevelDebug}))
+
+			handler := RecovererMiddleware(logger)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				panic(tt.value)
+			}))
+
+			rec := httptest.NewRecorder()
+			func() {
+				defer func() {
+					if value := recover(); (value != nil) != tt.wantRepanic {
+						t.Errorf("re-panicked with %v, want re-panic %v", value, tt.wantRepanic)
+					}
+				}()
+				handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/", nil))
+			}()
+
+			if rec.Code == http.StatusInternalServerError {
+				t.Error("client abort answered with a 500")
+			}
+			for _, entry := range logEntries(t, &out) {
+				if entry["level"] == "ERROR" {
+					t.Errorf("client abort logged as an application error: %v", entry)
+				}
+			}
+		})
+	}
+}
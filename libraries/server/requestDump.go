@@ -0,0 +1,98 @@
+package server
+
+import (
+	"bytes"
+	"io"
+	"log/slog"
+	"net/http"
+	"strings"
+)
+
+// maxDumpBodyBytes caps how much of a body RequestDumpMiddleware logs.
+const maxDumpBodyBytes = 64 << 10
+
+// redactedHeaders never show up in request dumps.
+var redactedHeaders = map[string]bool{
+	"Authorization":       true,
+	"Proxy-Authorization": true,
+	"Cookie":              true,
+	"Set-Cookie":          true,
+	"X-Api-Key":           true,
+}
+
+// redactedQueryParams never show up in request dumps, matched case-insensitively.
+var redactedQueryParams = map[string]bool{
+	"token":         true,
+	"access_token":  true,
+	"refresh_token": true,
+	"password":      true,
+	"secret":        true,
+	"client_secret": true,
+	"api_key":       true,
+	"apikey":        true,
+}
+
+// RequestDumpMiddleware logs the complete request, headers, query and body,
+// for paths under prefix, with credentials redacted and the body restored for
+// the handler. It only does so when env is the development one, elsewhere it
+// passes requests straight through.
+func RequestDumpMiddleware(env *Variables, prefix string) func(http.Handler) http.Handler {
+	enabled := isDevelopment(env.Env())
+
+	return func(next http.Handler) http.Handler {
+		if !enabled {
+			return next
+		}
+
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if !strings.HasPrefix(r.URL.Path, prefix) {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			headers := make(map[string]string, len(r.Header))
+			for name, values := range r.Header {
+				if redactedHeaders[name] {
+					headers[name] = "[REDACTED]"
+					continue
+				}
+				headers[name] = strings.Join(values, ", ")
+			}
+
+			query := r.URL.Query()
+			for name := range query {
+				if redactedQueryParams[strings.ToLower(name)] {
+					query[name] = []string{"[REDACTED]"}
+				}
+			}
+
+			var body []byte
+			truncated := false
+			if r.Body != nil && r.Body != http.NoBody {
+				// read one byte past the cap to know whether there is more
+				body, _ = io.ReadAll(io.LimitReader(r.Body, maxDumpBodyBytes+1))
+				r.Body = struct {
+					io.Reader
+					io.Closer
+				}{io.MultiReader(bytes.NewReader(body), r.Body), r.Body}
+
+				if len(body) > maxDumpBodyBytes {
+					body, truncated = body[:maxDumpBodyBytes], true
+				}
+			}
+
+			slog.DebugContext(r.Context(), "request dump",
+				slog.String("method", r.Method),
+				slog.String("path", r.URL.Path),
+				slog.String("proto", r.Proto),
+				slog.Any("headers", headers),
+				slog.Any("query", query),
+				slog.String("body", string(body)),
+				slog.Bool("body_truncated", truncated),
+				slog.String("trace_id", TraceID(r.Context())),
+			)
+
+			next.ServeHTTP(w, r)
+		})
+	}
+}
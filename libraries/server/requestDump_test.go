@@ -0,0 +1,71 @@
+package server
+
+import (
+	"bytes"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestRequestDumpMiddleware(t *testing.T) {
+	tests := []struct {
+		name     string
+		env      string
+		target   string
+		wantDump bool
+		want     []string
+		wantNot  []string
+	}{
+		{
+			name:     "redacts credentials",
+			env:      "development",
+			target:   "/api/items?page=2&token=s3cret&API_KEY=k3y",
+			wantDump: true,
+			want:     []string{"page:[2]", "token:[[REDACTED]]", "API_KEY:[[REDACTED]]", "Authorization:[REDACTED]", `body="{\"name\":\"x\"}"`},
+			wantNot:  []string{"s3cret", "k3y", "Bearer"},
+		},
+		{name: "skips other paths", env: "development", target: "/health"},
+		{name: "disabled outside development", env: "production", target: "/api/items"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var out bytes.Buffer
+			previous := slog.Default()
+			slog.SetDefault(slog.New(slog.NewTextHandler(&out, &slog.HandlerOptions{Level: slog.LevelDebug})))
+			defer slog.SetDefault(previous)
+
+			var got string
+			handler := RequestDumpMiddleware(&Variables{env: tt.env}, "/api")(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				body, _ := io.ReadAll(r.Body)
+				got = string(body)
+			}))
+
+			req := httptest.NewRequest(http.MethodPost, tt.target, strings.NewReader(`{"name":"x"}`))
+			req.Header.Set("Authorization", "Bearer s3cret")
+			handler.ServeHTTP(httptest.NewRecorder(), req)
+
+			if got != `{"name":"x"}` {
+				t.Errorf("handler body = %q, want it restored", got)
+			}
+
+			dump := out.String()
+			if dumped := strings.Contains(dump, "request dump"); dumped != tt.wantDump {
+				t.Fatalf("dumped = %v, want %v: %s", dumped, tt.wantDump, dump)
+			}
+			for _, want := range tt.want {
+				if !strings.Contains(dump, want) {
+					t.Errorf("dump missing %q: %s", want, dump)
+				}
+			}
+			for _, secret := range tt.wantNot {
+				if strings.Contains(dump, secret) {
+					t.Errorf("dump leaks %q: %s", secret, dump)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,54 @@
+package server
+
+import (
+	"context"
+	"net/http"
+	"sync"
+)
+
+const cleanupsKey contextKey = "request_cleanups"
+
+type requestCleanups struct {
+	mu  sync.Mutex
+	fns []func()
+}
+
+// OnRequestEnd registers fn to run once the request completes, panics
+// included, like a defer that survives helper boundaries. Cleanups run in
+// reverse registration order. It is a no-op on requests not served through
+// RequestCleanupMiddleware.
+func OnRequestEnd(ctx context.Context, fn func()) {
+	cleanups, ok := ctx.Value(cleanupsKey).(*requestCleanups)
+	if !ok {
+		return
+	}
+
+	cleanups.mu.Lock()
+	defer cleanups.mu.Unlock()
+
+	cleanups.fns = append(cleanups.fns, fn)
+}
+
+// RequestCleanupMiddleware runs the functions registered with OnRequestEnd
+// when the request completes. Place it inside the recoverer so cleanups run
+// before a panic is turned into a 500.
+func RequestCleanupMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		cleanups := &requestCleanups{}
+		defer cleanups.run()
+
+		ctx := context.WithValue(r.Context(), cleanupsKey, cleanups)
+		next.ServeHTTP(w, r.WithContext(ctx))
+	})
+}
+
+func (c *requestCleanups) run() {
+	c.mu.Lock()
+	fns := c.fns
+	c.fns = nil
+	c.mu.Unlock()
+
+	for i := len(fns) - 1; i >= 0; i-- {
+		fns[i]()
+	}
+}
@@ -0,0 +1,75 @@
+package server
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+
+	"github.com/himtar/go-boilerplate/pkg/logger"
+)
+
+func TestRequestCleanupMiddleware(t *testing.T) {
+	tests := []struct {
+		name  string
+		panic bool
+	}{
+		{name: "normal completion"},
+		{name: "handler panics", panic: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var ran []string
+			handler := RequestCleanupMiddleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				OnRequestEnd(r.Context(), func() { ran = append(ran, "close rows") })
+				OnRequestEnd(r.Context(), func() { ran = append(ran, "release lease") })
+
+				if tt.panic {
+					panic("boom")
+				}
+			}))
+
+			func() {
+				defer func() {
+					if value := recover(); (value != nil) != tt.panic {
+						t.Errorf("recovered %v, want a panic %v", value, tt.panic)
+					}
+				}()
+				handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/", nil))
+			}()
+
+			if want := []string{"release lease", "close rows"}; !reflect.DeepEqual(ran, want) {
+				t.Errorf("cleanups ran %v, want %v", ran, want)
+			}
+		})
+	}
+}
+
+func TestRequestCleanupMiddlewareInsideRecoverer(t *testing.T) {
+	cleaned := false
+	handler := RecovererMiddleware(logger.DiscardLogger().Logger)(RequestCleanupMiddleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		OnRequestEnd(r.Context(), func() { cleaned = true })
+		panic("boom")
+	})))
+
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/", nil))
+
+	if !cleaned {
+		t.Error("cleanup did not run on panic")
+	}
+	if rec.Code != http.StatusInternalServerError {
+		t.Errorf("status = %d, want %d", rec.Code, http.StatusInternalServerError)
+	}
+}
+
+func TestOnRequestEndWithoutMiddleware(t *testing.T) {
+	called := false
+	OnRequestEnd(context.Background(), func() { called = true })
+
+	if called {
+		t.Error("cleanup ran outside a request")
+	}
+}
@@ -0,0 +1,43 @@
+package server
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/go-chi/chi/middleware"
+)
+
+// RequestIDHeader carries the request ID in both directions.
+const RequestIDHeader = "X-Request-ID"
+
+// RequestIDMiddleware reuses the incoming X-Request-ID or generates one and
+// stores it under chi's middleware.RequestIDKey, so middleware.GetReqID keeps working.
+func RequestIDMiddleware(next http.Handler) http.Handler {
+	return NewRequestIDMiddleware(nil)(next)
+}
+
+// NewRequestIDMiddleware is RequestIDMiddleware with a custom ID generator, random hex when nil.
+func NewRequestIDMiddleware(generate IDGenerator) func(http.Handler) http.Handler {
+	if generate == nil {
+		generate = newTraceID
+	}
+
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			requestID := r.Header.Get(RequestIDHeader)
+			if requestID == "" {
+				requestID = generate()
+			}
+
+			w.Header().Set(RequestIDHeader, requestID)
+			ctx := context.WithValue(r.Context(), middleware.RequestIDKey, requestID)
+
+			next.ServeHTTP(w, r.WithContext(ctx))
+		})
+	}
+}
+
+// RequestID returns the request ID stored by RequestIDMiddleware, "" if absent.
+func RequestID(ctx context.Context) string {
+	return middleware.GetReqID(ctx)
+}
@@ -0,0 +1,107 @@
+package server
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strconv"
+	"testing"
+
+	"github.com/go-chi/chi/middleware"
+	"github.com/himtar/go-boilerplate/pkg/trace"
+)
+
+// sequence returns an IDGenerator yielding prefix-1, prefix-2, ...
+func sequence(prefix string) IDGenerator {
+	n := 0
+	return func() string {
+		n++
+		return prefix + "-" + strconv.Itoa(n)
+	}
+}
+
+func TestIDMiddlewaresUseGenerator(t *testing.T) {
+	tests := []struct {
+		name       string
+		middleware func(IDGenerator) func(http.Handler) http.Handler
+		header     string
+		fromCtx    func(ctx context.Context) string
+	}{
+		{name: "trace ID", middleware: NewTraceIDMiddleware, header: TraceIDHeader, fromCtx: TraceID},
+		{name: "request ID", middleware: NewRequestIDMiddleware, header: RequestIDHeader, fromCtx: RequestID},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var seen []string
+			handler := tt.middleware(sequence("id"))(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				seen = append(seen, tt.fromCtx(r.Context()))
+			}))
+
+			var echoed []string
+			for _, incoming := range []string{"", "", "client-supplied", ""} {
+				req := httptest.NewRequest(http.MethodGet, "/", nil)
+				if incoming != "" {
+					req.Header.Set(tt.header, incoming)
+				}
+
+				rec := httptest.NewRecorder()
+				handler.ServeHTTP(rec, req)
+				echoed = append(echoed, rec.Header().Get(tt.header))
+			}
+
+			want := []string{"id-1", "id-2", "client-supplied", "id-3"}
+			if !reflect.DeepEqual(seen, want) {
+				t.Errorf("context IDs = %v, want %v", seen, want)
+			}
+			if !reflect.DeepEqual(echoed, want) {
+				t.Errorf("header IDs = %v, want %v", echoed, want)
+			}
+		})
+	}
+}
+
+func TestIDMiddlewaresDefaultGenerator(t *testing.T) {
+	var traceID, requestID string
+	handler := TraceIDMiddleware(RequestIDMiddleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		traceID, requestID = TraceID(r.Context()), RequestID(r.Context())
+	})))
+	handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/", nil))
+
+	if len(traceID) != 32 || len(requestID) != 32 {
+		t.Errorf("trace ID %q and request ID %q, want 32 hex characters each", traceID, requestID)
+	}
+	if traceID == requestID {
+		t.Error("trace and request ID share one value")
+	}
+}
+
+func TestContextIDAccessors(t *testing.T) {
+	tests := []struct {
+		name          string
+		ctx           context.Context
+		wantTraceID   string
+		wantRequestID string
+	}{
+		{name: "absent", ctx: context.Background()},
+		{
+			name:          "present",
+			ctx:           context.WithValue(trace.NewContext(context.Background(), "trace-1"), middleware.RequestIDKey, "req-1"),
+			wantTraceID:   "trace-1",
+			wantRequestID: "req-1",
+		},
+		{name: "wrong type", ctx: context.WithValue(context.Background(), middleware.RequestIDKey, 42)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := TraceID(tt.ctx); got != tt.wantTraceID {
+				t.Errorf("TraceID() = %q, want %q", got, tt.wantTraceID)
+			}
+			if got := RequestID(tt.ctx); got != tt.wantRequestID {
+				t.Errorf("RequestID() = %q, want %q", got, tt.wantRequestID)
+			}
+		})
+	}
+}
@@ -0,0 +1,40 @@
+package server
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/himtar/go-boilerplate/pkg/errors"
+	"github.com/himtar/go-boilerplate/pkg/helpers"
+)
+
+// RequireHTTPSMiddleware only lets HTTPS requests through. Behind a TLS
+// terminating proxy the scheme is taken from X-Forwarded-Proto, otherwise from
+// the connection itself. Plain HTTP requests get a 301 to the https URL when
+// redirect is set and a 403 otherwise.
+func RequireHTTPSMiddleware(behindProxy, redirect bool) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if isHTTPS(r, behindProxy) {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			if !redirect {
+				errors.Forbidden(w, "HTTPS Required !")
+				return
+			}
+
+			target := "https://" + r.Host + r.URL.RequestURI()
+			http.Redirect(w, r, target, http.StatusMovedPermanently)
+		})
+	}
+}
+
+func isHTTPS(r *http.Request, behindProxy bool) bool {
+	if behindProxy {
+		return strings.EqualFold(helpers.LastForwarded(r.Header.Get("X-Forwarded-Proto")), "https")
+	}
+
+	return r.TLS != nil
+}
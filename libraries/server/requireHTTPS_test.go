@@ -0,0 +1,56 @@
+package server
+
+import (
+	"crypto/tls"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestRequireHTTPSMiddleware(t *testing.T) {
+	tests := []struct {
+		name         string
+		behindProxy  bool
+		redirect     bool
+		tls          bool
+		proto        string
+		wantStatus   int
+		wantLocation string
+	}{
+		{name: "direct HTTP redirected", redirect: true, wantStatus: http.StatusMovedPermanently, wantLocation: "https://example.com/orders?page=2"},
+		{name: "direct HTTP rejected", wantStatus: http.StatusForbidden},
+		{name: "direct HTTPS passes", redirect: true, tls: true, wantStatus: http.StatusOK},
+		{name: "direct HTTPS passes when rejecting", tls: true, wantStatus: http.StatusOK},
+		{name: "direct ignores forwarded proto", proto: "https", wantStatus: http.StatusForbidden},
+		{name: "proxied HTTP redirected", behindProxy: true, redirect: true, proto: "http", wantStatus: http.StatusMovedPermanently, wantLocation: "https://example.com/orders?page=2"},
+		{name: "proxied HTTP rejected", behindProxy: true, proto: "http", wantStatus: http.StatusForbidden},
+		{name: "proxied HTTPS passes", behindProxy: true, redirect: true, proto: "https", wantStatus: http.StatusOK},
+		{name: "proxied HTTPS passes when rejecting", behindProxy: true, proto: "HTTPS", wantStatus: http.StatusOK},
+		{name: "proxied uses last hop", behindProxy: true, proto: "https, http", wantStatus: http.StatusForbidden},
+		{name: "proxied without header rejected", behindProxy: true, tls: true, wantStatus: http.StatusForbidden},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			handler := RequireHTTPSMiddleware(tt.behindProxy, tt.redirect)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+
+			req := httptest.NewRequest(http.MethodGet, "http://example.com/orders?page=2", nil)
+			if tt.tls {
+				req.TLS = &tls.ConnectionState{}
+			}
+			if tt.proto != "" {
+				req.Header.Set("X-Forwarded-Proto", tt.proto)
+			}
+
+			rec := httptest.NewRecorder()
+			handler.ServeHTTP(rec, req)
+
+			if rec.Code != tt.wantStatus {
+				t.Errorf("status = %d, want %d", rec.Code, tt.wantStatus)
+			}
+			if got := rec.Header().Get("Location"); got != tt.wantLocation {
+				t.Errorf("Location = %q, want %q", got, tt.wantLocation)
+			}
+		})
+	}
+}
@@ -0,0 +1,46 @@
+package server
+
+import (
+	"context"
+	"net/http"
+	"strings"
+
+	"github.com/himtar/go-boilerplate/pkg/response"
+)
+
+const requiredHeadersKey contextKey = "required_headers"
+
+// RequireHeadersMiddleware rejects requests missing any of headers with a 400
+// listing every missing one. Values of present headers are stored in the
+// context, see RequiredHeader.
+func RequireHeadersMiddleware(headers ...string) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			values := make(map[string]string, len(headers))
+			var missing []string
+
+			for _, name := range headers {
+				value := r.Header.Get(name)
+				if value == "" {
+					missing = append(missing, name)
+					continue
+				}
+				values[http.CanonicalHeaderKey(name)] = value
+			}
+
+			if len(missing) > 0 {
+				response.SendBadRequest(w, "Missing required headers: "+strings.Join(missing, ", "))
+				return
+			}
+
+			ctx := context.WithValue(r.Context(), requiredHeadersKey, values)
+			next.ServeHTTP(w, r.WithContext(ctx))
+		})
+	}
+}
+
+// RequiredHeader returns the value of a header checked by RequireHeadersMiddleware.
+func RequiredHeader(ctx context.Context, name string) string {
+	values, _ := ctx.Value(requiredHeadersKey).(map[string]string)
+	return values[http.CanonicalHeaderKey(name)]
+}
@@ -0,0 +1,71 @@
+package server
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/himtar/go-boilerplate/pkg/response"
+)
+
+func TestRequireHeadersMiddleware(t *testing.T) {
+	tests := []struct {
+		name        string
+		headers     map[string]string
+		wantStatus  int
+		wantMessage string
+	}{
+		{
+			name:       "all present",
+			headers:    map[string]string{"X-Tenant-ID": "acme", "X-Client-Version": "2.1"},
+			wantStatus: http.StatusOK,
+		},
+		{
+			name:        "one missing",
+			headers:     map[string]string{"X-Tenant-ID": "acme"},
+			wantStatus:  http.StatusBadRequest,
+			wantMessage: "Missing required headers: X-Client-Version",
+		},
+		{
+			name:        "several missing",
+			wantStatus:  http.StatusBadRequest,
+			wantMessage: "Missing required headers: X-Tenant-ID, X-Client-Version",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var tenant string
+			handler := RequireHeadersMiddleware("X-Tenant-ID", "X-Client-Version")(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				tenant = RequiredHeader(r.Context(), "x-tenant-id")
+			}))
+
+			req := httptest.NewRequest(http.MethodGet, "/", nil)
+			for name, value := range tt.headers {
+				req.Header.Set(name, value)
+			}
+			rec := httptest.NewRecorder()
+			handler.ServeHTTP(rec, req)
+
+			if rec.Code != tt.wantStatus {
+				t.Fatalf("status = %d, want %d", rec.Code, tt.wantStatus)
+			}
+
+			if tt.wantStatus == http.StatusOK {
+				if tenant != "acme" {
+					t.Errorf("RequiredHeader() = %q, want %q", tenant, "acme")
+				}
+				return
+			}
+
+			var res response.Response
+			if err := json.Unmarshal(rec.Body.Bytes(), &res); err != nil {
+				t.Fatal(err)
+			}
+			if res.Message != tt.wantMessage {
+				t.Errorf("message = %q, want %q", res.Message, tt.wantMessage)
+			}
+		})
+	}
+}
@@ -0,0 +1,31 @@
+package server
+
+import (
+	"fmt"
+	"net"
+	"os"
+	"strconv"
+)
+
+// restartListenerFDEnv tells a process started by a graceful restart which
+// inherited file descriptor holds the listening socket.
+const restartListenerFDEnv = "SERVER_LISTENER_FD"
+
+// listen reuses the listener inherited from the parent after a graceful
+// restart and opens a new one on addr otherwise.
+func listen(addr string) (net.Listener, error) {
+	fd := os.Getenv(restartListenerFDEnv)
+	if fd == "" {
+		return net.Listen("tcp", addr)
+	}
+
+	n, err := strconv.Atoi(fd)
+	if err != nil {
+		return nil, fmt.Errorf("invalid %s %q: %w", restartListenerFDEnv, fd, err)
+	}
+
+	file := os.NewFile(uintptr(n), "listener")
+	defer file.Close()
+
+	return net.FileListener(file)
+}
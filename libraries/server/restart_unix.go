@@ -0,0 +1,45 @@
+//go:build !windows
+
+package server
+
+import (
+	"errors"
+	"net"
+	"os"
+	"os/exec"
+	"syscall"
+)
+
+// restartSignal triggers a graceful restart when ServerConfig.GracefulRestart is set.
+var restartSignal os.Signal = syscall.SIGUSR2
+
+// startChild re-executes the current binary handing it the listening socket,
+// so the new process serves on it while this one drains.
+func startChild(l net.Listener) error {
+	tcp, ok := l.(*net.TCPListener)
+	if !ok {
+		return errors.New("graceful restart needs a TCP listener")
+	}
+
+	file, err := tcp.File()
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	executable, err := os.Executable()
+	if err != nil {
+		return err
+	}
+
+	cmd := exec.Command(executable, os.Args[1:]...)
+	cmd.Stdin = os.Stdin
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+
+	// ExtraFiles[0] becomes fd 3 in the child
+	cmd.ExtraFiles = []*os.File{file}
+	cmd.Env = append(os.Environ(), restartListenerFDEnv+"=3")
+
+	return cmd.Start()
+}
@@ -0,0 +1,120 @@
+//go:build !windows
+
+package server
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"os"
+	"testing"
+	"time"
+)
+
+// serveRestartedChild is the part of TestGracefulRestart running in the
+// process started by startChild, it serves on the inherited listener until
+// asked to stop.
+func serveRestartedChild(t *testing.T) {
+	l, err := listen("")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	stopped := make(chan struct{})
+	mux := http.NewServeMux()
+	mux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+		io.WriteString(w, "child")
+	})
+	mux.HandleFunc("/stop", func(w http.ResponseWriter, r *http.Request) {
+		close(stopped)
+	})
+
+	srv := &http.Server{Handler: mux}
+	go srv.Serve(l)
+
+	select {
+	case <-stopped:
+	case <-time.After(10 * time.Second):
+	}
+	srv.Close()
+}
+
+func TestGracefulRestart(t *testing.T) {
+	if os.Getenv(restartListenerFDEnv) != "" {
+		serveRestartedChild(t)
+		return
+	}
+
+	l, err := listen("127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	url := "http://" + l.Addr().String()
+
+	srv := &http.Server{Handler: http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		io.WriteString(w, "parent")
+	})}
+	go srv.Serve(l)
+
+	client := &http.Client{
+		Timeout:   time.Second,
+		Transport: &http.Transport{DisableKeepAlives: true},
+	}
+	get := func(path string) string {
+		t.Helper()
+		res, err := client.Get(url + path)
+		if err != nil {
+			t.Fatalf("request failed during the handoff: %v", err)
+		}
+		defer res.Body.Close()
+
+		body, _ := io.ReadAll(res.Body)
+		return string(body)
+	}
+
+	if got := get("/"); got != "parent" {
+		t.Fatalf("body = %q, want parent", got)
+	}
+
+	// the child re-runs this test binary, only this test and without its output
+	args, stdout := os.Args, os.Stdout
+	os.Args = []string{os.Args[0], "-test.run=^TestGracefulRestart$"}
+	os.Stdout, _ = os.Open(os.DevNull)
+	err = startChild(l)
+	os.Stdout.Close()
+	os.Args, os.Stdout = args, stdout
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if err := srv.Shutdown(ctx); err != nil {
+		t.Fatal(err)
+	}
+
+	// the parent stopped accepting, every request now has to reach the child
+	deadline := time.Now().Add(5 * time.Second)
+	for get("/") != "child" {
+		if time.Now().After(deadline) {
+			t.Fatal("child never took over the socket")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	for i := 0; i < 5; i++ {
+		if got := get("/"); got != "child" {
+			t.Errorf("request %d body = %q, want child", i, got)
+		}
+	}
+
+	get("/stop")
+}
+
+func TestListenInvalidInheritedFD(t *testing.T) {
+	t.Setenv(restartListenerFDEnv, "not-a-fd")
+
+	if _, err := listen("127.0.0.1:0"); err == nil {
+		t.Error("invalid inherited fd accepted")
+	}
+}
@@ -0,0 +1,16 @@
+//go:build windows
+
+package server
+
+import (
+	"errors"
+	"net"
+	"os"
+)
+
+// restartSignal is nil as graceful restarts aren't supported on windows.
+var restartSignal os.Signal
+
+func startChild(l net.Listener) error {
+	return errors.New("graceful restart is not supported on windows")
+}
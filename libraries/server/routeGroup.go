@@ -0,0 +1,17 @@
+package server
+
+import (
+	"net/http"
+)
+
+// RouteGroup mounts a sub-router at prefix with mws applied and lets fn
+// register its routes, e.g. /api/v1 behind auth and rate limiting. The
+// middlewares only wrap the group's routes, not the parent's other routes.
+func (r *HTTPRouter) RouteGroup(prefix string, mws []func(http.Handler) http.Handler, fn func(r *HTTPRouter)) *HTTPRouter {
+	return r.Route(prefix, func(child *HTTPRouter) {
+		child.Use(mws...)
+		if fn != nil {
+			fn(child)
+		}
+	})
+}
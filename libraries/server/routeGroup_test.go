@@ -0,0 +1,53 @@
+package server
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/go-chi/chi"
+)
+
+func TestRouteGroup(t *testing.T) {
+	tag := func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("X-Group", "v1")
+			next.ServeHTTP(w, r)
+		})
+	}
+
+	app, err := NewHTTPRouter(chi.NewRouter(), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	app.RouteGroup("/api/v1", []func(http.Handler) http.Handler{tag}, func(r *HTTPRouter) {
+		r.Get("/users", func(w http.ResponseWriter, r *http.Request) {})
+	})
+	app.Get("/users", func(w http.ResponseWriter, r *http.Request) {})
+
+	tests := []struct {
+		name       string
+		path       string
+		wantStatus int
+		wantTag    string
+	}{
+		{name: "child route gets prefix and middleware", path: "/api/v1/users", wantStatus: http.StatusOK, wantTag: "v1"},
+		{name: "sibling route is untouched", path: "/users", wantStatus: http.StatusOK},
+		{name: "child route is not served without prefix", path: "/api/users", wantStatus: http.StatusNotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := httptest.NewRecorder()
+			app.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, tt.path, nil))
+
+			if rec.Code != tt.wantStatus {
+				t.Errorf("status = %d, want %d", rec.Code, tt.wantStatus)
+			}
+			if got := rec.Header().Get("X-Group"); got != tt.wantTag {
+				t.Errorf("X-Group = %q, want %q", got, tt.wantTag)
+			}
+		})
+	}
+}
@@ -0,0 +1,91 @@
+package server
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+
+	"github.com/himtar/go-boilerplate/pkg/helpers"
+	"github.com/himtar/go-boilerplate/pkg/response"
+	"github.com/santhosh-tekuri/jsonschema/v5"
+)
+
+// SchemaFieldError describes one location of a body failing its JSON Schema.
+type SchemaFieldError struct {
+	Field string `json:"field"`
+	Error string `json:"error"`
+}
+
+// SchemaValidationMiddleware validates JSON request bodies against schema and
+// answers mismatches with a 422 listing the failing fields. Valid bodies are
+// restored for the handler. Requests without a body pass through untouched.
+// It panics if schema doesn't compile, like regexp.MustCompile.
+func SchemaValidationMiddleware(schema []byte) func(http.Handler) http.Handler {
+	compiler := jsonschema.NewCompiler()
+	if err := compiler.AddResource("schema.json", bytes.NewReader(schema)); err != nil {
+		panic("server: invalid JSON schema: " + err.Error())
+	}
+
+	compiled, err := compiler.Compile("schema.json")
+	if err != nil {
+		panic("server: invalid JSON schema: " + err.Error())
+	}
+
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.Body == nil || r.Body == http.NoBody {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			body, err := io.ReadAll(http.MaxBytesReader(w, r.Body, helpers.MaxJSONBodyBytes))
+			if err != nil {
+				response.SendBadRequest(w, "Unable to read request body")
+				return
+			}
+
+			// the validator expects numbers decoded as json.Number
+			decoder := json.NewDecoder(bytes.NewReader(body))
+			decoder.UseNumber()
+
+			var instance interface{}
+			if err := decoder.Decode(&instance); err != nil {
+				response.SendBadRequest(w, "Invalid JSON body")
+				return
+			}
+
+			if err := compiled.Validate(instance); err != nil {
+				var validationErr *jsonschema.ValidationError
+				if !errors.As(err, &validationErr) {
+					response.SendBadRequest(w, err.Error())
+					return
+				}
+
+				response.Send(w, http.StatusUnprocessableEntity, "Request body does not match schema", schemaFieldErrors(validationErr))
+				return
+			}
+
+			r.Body = io.NopCloser(bytes.NewReader(body))
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// schemaFieldErrors flattens the error tree to its leaves, the actual failures.
+func schemaFieldErrors(err *jsonschema.ValidationError) []SchemaFieldError {
+	if len(err.Causes) == 0 {
+		field := err.InstanceLocation
+		if field == "" {
+			field = "/"
+		}
+		return []SchemaFieldError{{Field: field, Error: err.Message}}
+	}
+
+	var fieldErrors []SchemaFieldError
+	for _, cause := range err.Causes {
+		fieldErrors = append(fieldErrors, schemaFieldErrors(cause)...)
+	}
+	return fieldErrors
+}
@@ -0,0 +1,94 @@
+package server
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/himtar/go-boilerplate/pkg/response"
+)
+
+const orderSchema = `{
+	"type": "object",
+	"required": ["sku", "quantity"],
+	"properties": {
+		"sku": {"type": "string", "minLength": 1},
+		"quantity": {"type": "integer", "minimum": 1}
+	}
+}`
+
+func TestSchemaValidationMiddleware(t *testing.T) {
+	tests := []struct {
+		name       string
+		body       string
+		wantStatus int
+		wantFields []string
+	}{
+		{name: "conforming payload", body: `{"sku":"A-1","quantity":2}`, wantStatus: http.StatusOK},
+		{name: "wrong type and missing field", body: `{"quantity":"two"}`, wantStatus: http.StatusUnprocessableEntity, wantFields: []string{"/", "/quantity"}},
+		{name: "below minimum", body: `{"sku":"A-1","quantity":0}`, wantStatus: http.StatusUnprocessableEntity, wantFields: []string{"/quantity"}},
+		{name: "malformed JSON", body: `{"sku":`, wantStatus: http.StatusBadRequest},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var received string
+			handler := SchemaValidationMiddleware([]byte(orderSchema))(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				body, _ := io.ReadAll(r.Body)
+				received = string(body)
+			}))
+
+			rec := httptest.NewRecorder()
+			handler.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/orders", strings.NewReader(tt.body)))
+
+			if rec.Code != tt.wantStatus {
+				t.Fatalf("status = %d, want %d: %s", rec.Code, tt.wantStatus, rec.Body.String())
+			}
+
+			if tt.wantStatus == http.StatusOK {
+				if received != tt.body {
+					t.Errorf("handler got body %q, want %q", received, tt.body)
+				}
+				return
+			}
+
+			if tt.wantFields == nil {
+				return
+			}
+
+			var res struct {
+				response.Response
+				Data []SchemaFieldError `json:"data"`
+			}
+			if err := json.Unmarshal(rec.Body.Bytes(), &res); err != nil {
+				t.Fatal(err)
+			}
+
+			fields := make(map[string]bool)
+			for _, fieldErr := range res.Data {
+				if fieldErr.Error == "" {
+					t.Errorf("field %s has no error message", fieldErr.Field)
+				}
+				fields[fieldErr.Field] = true
+			}
+			for _, field := range tt.wantFields {
+				if !fields[field] {
+					t.Errorf("no error for %s in %+v", field, res.Data)
+				}
+			}
+		})
+	}
+}
+
+func TestSchemaValidationMiddlewareInvalidSchema(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Error("invalid schema did not panic")
+		}
+	}()
+
+	SchemaValidationMiddleware([]byte(`{"type": 12}`))
+}
@@ -1,49 +1,370 @@
 package server
 
 import (
-	"fmt"
+	"context"
+	"errors"
 	"log"
+	"log/slog"
+	"net"
 	"net/http"
 	"os"
 	"os/signal"
-	"syscall"
+	"sync/atomic"
 	"time"
 
 	"github.com/go-chi/chi"
 	"github.com/go-chi/chi/middleware"
+	"github.com/himtar/go-boilerplate/pkg/logger"
+	"github.com/himtar/go-boilerplate/pkg/response"
+	"golang.org/x/net/netutil"
 )
 
-func prepareServer (app *chi.Mux) *chi.Mux {
+// Version is the build version, set at build time with
+// -ldflags "-X github.com/himtar/go-boilerplate/libraries/server.Version=v1.2.3".
+var Version = ""
+
+// ServerConfig holds the runtime settings of the http server.
+type ServerConfig struct {
+	Addr            string
+	ShutdownTimeout time.Duration
+
+	// StartedAt is stamped when the server is built and used to report uptime on shutdown.
+	StartedAt time.Time
+
+	// Logger is closed last on shutdown so the final entries get persisted.
+	Logger *logger.Logger
+
+	// OnStartup runs before the server starts listening, an error or panic aborts the start.
+	OnStartup func(ctx context.Context) error
+
+	// Shutdown stops registered background workers along with the server.
+	Shutdown *ShutdownCoordinator
+
+	// OnWarmup runs once the server is listening, e.g. to prime caches. Readiness
+	// reports 503 until it succeeds, failures are retried every WarmupRetryInterval.
+	OnWarmup func(ctx context.Context) error
+
+	// WarmupRetryInterval is the pause between failed warm-ups, 5s when zero.
+	WarmupRetryInterval time.Duration
+
+	// OnShutdown runs once the server stopped serving, failures are logged.
+	OnShutdown func(ctx context.Context) error
+
+	// ShutdownHookRetries retries a failing OnShutdown with exponential backoff
+	// until the shutdown deadline, zero disables retrying.
+	ShutdownHookRetries int
+
+	// MaxConnections caps concurrently open connections at the socket level, zero means unlimited.
+	MaxConnections int
+
+	// Middlewares is the stack every request goes through, see
+	// BuildDefaultMiddlewares which BuildAndStartServer uses when it is nil.
+	Middlewares []func(http.Handler) http.Handler
+
+	// GracefulRestart makes SIGUSR2 start a new copy of the binary on the same
+	// socket and drain this one, for zero downtime deploys. Unix only.
+	GracefulRestart bool
+
+	// MaxHeaderBytes caps the size of request headers, http.DefaultMaxHeaderBytes
+	// (1MB) when zero. Larger headers are rejected with 431.
+	MaxHeaderBytes int
+
+	// AllowLargeHeaders raises the header cap to largeMaxHeaderBytes for trusted
+	// internal listeners whose callers send big JWTs or many cookies. Keep it off
+	// for anything reachable from the internet.
+	AllowLargeHeaders bool
+}
+
+// largeMaxHeaderBytes is the header cap used with AllowLargeHeaders.
+const largeMaxHeaderBytes = 8 << 20
+
+// Bounds for the shutdown timeout, a zero or huge value would break shutdown.
+const (
+	minShutdownTimeout = time.Second
+	maxShutdownTimeout = 5 * time.Minute
+)
+
+// DefaultServerConfig builds the server config from the env variables.
+func DefaultServerConfig(env *Variables) *ServerConfig {
+	timeout := clampShutdownTimeout(env.ShutdownTimeout())
+
+	return &ServerConfig{
+		Addr:            env.Port(),
+		ShutdownTimeout: timeout,
+		Shutdown:        NewShutdownCoordinator(timeout),
+	}
+}
+
+// clampShutdownTimeout keeps timeout within 1s to 5m, warning when it had to adjust it.
+func clampShutdownTimeout(timeout time.Duration) time.Duration {
+	clamped := timeout
+	switch {
+	case timeout < minShutdownTimeout:
+		clamped = minShutdownTimeout
+	case timeout > maxShutdownTimeout:
+		clamped = maxShutdownTimeout
+	default:
+		return timeout
+	}
+
+	slog.Warn("SHUTDOWN_TIMEOUT_MS out of range, clamped",
+		slog.Duration("configured", timeout),
+		slog.Duration("used", clamped),
+	)
+	return clamped
+}
+
+// maxHeaderBytes resolves the header size cap from the config.
+func (cfg *ServerConfig) maxHeaderBytes() int {
+	limit := cfg.MaxHeaderBytes
+	if limit <= 0 {
+		limit = http.DefaultMaxHeaderBytes
+	}
+
+	if cfg.AllowLargeHeaders && limit < largeMaxHeaderBytes {
+		slog.Warn("large request headers allowed, only use this on internal listeners",
+			slog.Int("max_header_bytes", largeMaxHeaderBytes),
+		)
+		limit = largeMaxHeaderBytes
+	}
+
+	return limit
+}
+
+// countRequests increments counter for every request served.
+func countRequests(counter *atomic.Uint64) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			counter.Add(1)
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+func prepareServer (app *chi.Mux, served *atomic.Uint64, profiling bool, mws []func(http.Handler) http.Handler, l *logger.Logger) (*chi.Mux, error) {
 	chiServer := chi.NewRouter()
 
+	// handlers and middlewares find the app logger through logger.FromContext
+	if _, err := NewHTTPRouter(chiServer, l); err != nil {
+		return nil, err
+	}
+
 	// basic middleware setup
-	chiServer.Use(middleware.RequestID)
-	chiServer.Use(middleware.RealIP)
-	chiServer.Use(middleware.Logger)
-	chiServer.Use(middleware.Recoverer)
+	chiServer.Use(countRequests(served))
+	chiServer.Use(mws...)
+	chiServer.Use(RequestCleanupMiddleware)
+	chiServer.Use(DrainMiddleware)
 
 	// // Set a 60 sec timeout value on api request life
 	chiServer.Use(middleware.Timeout(60 * time.Second))
 
+	errorHandlers := response.DefaultErrorHandlers()
+	chiServer.NotFound(errorHandlers.NotFound)
+	chiServer.MethodNotAllowed(errorHandlers.MethodNotAllowed)
+
+	chiServer.Get(LivenessPath, LivenessHandler())
+	chiServer.Get(ReadinessPath, ReadinessHandler())
+	chiServer.Get(VersionPath, VersionHandler(BuildInfo()))
+
+	if profiling {
+		chiServer.Mount("/debug", middleware.Profiler())
+	}
+
 	// register mux
 	chiServer.Mount("/", app)
 
-	return chiServer
+	return chiServer, nil
 }
 
-func BuildAndStartServer(app *chi.Mux) {
-	stopChan := make(chan os.Signal, 1)
-	signal.Notify(stopChan, os.Interrupt, syscall.SIGTERM)
+// BuildAndStartServer serves app until SIGINT/SIGTERM, then shuts it down
+// gracefully. opts can adjust the config built from the env, e.g. to set hooks.
+func BuildAndStartServer(app *chi.Mux, opts ...func(cfg *ServerConfig)) {
+	// structured output from the very start, replaced once the env is loaded
+	slog.SetDefault(logger.Bootstrap())
 
 	env := LoadENVVariables()
-	server := prepareServer(app)
+
+	logConfig := logger.ConfigForEnv(env.ModuleName(), env.Env())
+	logConfig.ServiceVersion = Version
+
+	appLogger, err := logger.New(logConfig)
+	if err != nil {
+		slog.Error("Error building logger", slog.String("error", err.Error()))
+		os.Exit(1)
+	}
+	slog.SetDefault(appLogger.Logger)
+	RegisterReadinessCheck("logger", appLogger.HealthCheck)
+
+	response.SetPretty(env.Env() == "development")
+
+	cfg := DefaultServerConfig(env)
+	cfg.StartedAt = time.Now()
+	cfg.Logger = appLogger
+
+	for _, opt := range opts {
+		opt(cfg)
+	}
+
+	if cfg.Middlewares == nil {
+		cfg.Middlewares = BuildDefaultMiddlewares(env, cfg.Logger)
+	}
+
+	if err := runHook(context.Background(), "startup", cfg.OnStartup); err != nil {
+		slog.Error("startup hook failed, not starting server", slog.String("error", err.Error()))
+		cfg.Logger.Close()
+		os.Exit(1)
+	}
+
+	if cfg.Shutdown == nil {
+		cfg.Shutdown = NewShutdownCoordinator(cfg.ShutdownTimeout)
+	}
+
+	rs, err := startServer(cfg, app, env.Profiling())
+	if err != nil {
+		log.Fatalf("Error listening on %s: %v", cfg.Addr, err)
+	}
+
+	var restarted atomic.Bool
+	if cfg.GracefulRestart && restartSignal != nil {
+		go watchRestart(rs, &restarted)
+	}
+
+	cfg.Shutdown.awaitStop()
+
+	if !restarted.Load() {
+		log.Println("\n Shutting down")
+		// only a real stop fails readiness, on restart the new process takes over
+		draining.Store(true)
+	}
+
+	rs.shutdown()
+}
+
+// watchRestart hands the listener to a new process on restartSignal, then
+// stops the coordinator so this one drains. Failed restarts keep serving.
+func watchRestart(rs *runningServer, restarted *atomic.Bool) {
+	restartChan := make(chan os.Signal, 1)
+	signal.Notify(restartChan, restartSignal)
+	defer signal.Stop(restartChan)
+
+	for {
+		select {
+		case <-restartChan:
+		case <-rs.cfg.Shutdown.Context().Done():
+			return
+		}
+
+		if err := startChild(rs.listener); err != nil {
+			slog.Error("graceful restart failed, keep serving", slog.String("error", err.Error()))
+			continue
+		}
+
+		slog.Info("graceful restart: new process started, draining this one")
+		restarted.Store(true)
+		rs.cfg.Shutdown.cancel()
+		return
+	}
+}
+
+// runningServer is a started server along with what it takes to stop it.
+type runningServer struct {
+	cfg        *ServerConfig
+	srv        *http.Server
+	listener   net.Listener
+	served     atomic.Uint64
+	stopWarmup context.CancelFunc
+}
+
+// startServer listens on cfg.Addr and serves app in the background, with
+// pprof mounted at /debug when profiling is set.
+func startServer(cfg *ServerConfig, app *chi.Mux, profiling bool) (*runningServer, error) {
+	rs := &runningServer{cfg: cfg}
+
+	handler, err := prepareServer(app, &rs.served, profiling, cfg.Middlewares, cfg.Logger)
+	if err != nil {
+		return nil, err
+	}
+
+	rs.srv = &http.Server{
+		Addr:           cfg.Addr,
+		Handler:        handler,
+		MaxHeaderBytes: cfg.maxHeaderBytes(),
+	}
+
+	listener, err := listen(cfg.Addr)
+	if err != nil {
+		return nil, err
+	}
+	rs.listener = listener
+
+	// readiness has to fail before the server accepts, not once warm-up got scheduled
+	if cfg.OnWarmup != nil {
+		warmingUp.Store(true)
+	}
+
+	// keep the raw listener for graceful restarts, the limited one only wraps Accept
+	serveListener := listener
+	if cfg.MaxConnections > 0 {
+		serveListener = netutil.LimitListener(listener, cfg.MaxConnections)
+	}
 
 	// start the server
-	log.Println("\n Starting server on port", env)
+	log.Println("\n Starting server on port", listener.Addr())
+
+	go func() {
+		if err := rs.srv.Serve(serveListener); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			log.Fatalf("Server failed: %v", err)
+		}
+	}()
 
-	http.ListenAndServe(env.Port(), server)
+	warmupCtx, stopWarmup := context.WithCancel(context.Background())
+	rs.stopWarmup = stopWarmup
+	go runWarmup(warmupCtx, cfg.OnWarmup, cfg.WarmupRetryInterval)
 
-	<-stopChan
-	fmt.Println("\n Shutting down")
-	time.Sleep(5 * time.Second)
-}
\ No newline at end of file
+	return rs, nil
+}
+
+// shutdown drains the server and the registered workers within the shutdown
+// timeout, then runs the shutdown hook and closes the logger.
+func (rs *runningServer) shutdown() {
+	cfg := rs.cfg
+	rs.stopWarmup()
+
+	ctx, cancel := context.WithTimeout(context.Background(), cfg.ShutdownTimeout)
+	defer cancel()
+
+	// workers wind down while the server drains its in-flight requests
+	workersDone := make(chan struct{})
+	go func() {
+		defer close(workersDone)
+		if cfg.Shutdown != nil {
+			cfg.Shutdown.Shutdown(ctx)
+		}
+	}()
+
+	if err := rs.srv.Shutdown(ctx); err != nil {
+		slog.Error("forced shutdown", slog.String("error", err.Error()))
+		// the deadline passed with requests still running, cut their connections
+		if err := rs.srv.Close(); err != nil {
+			slog.Error("closing connections failed", slog.String("error", err.Error()))
+		}
+	} else {
+		slog.Info("server stopped gracefully",
+			slog.String("uptime", time.Since(cfg.StartedAt).Round(time.Second).String()),
+			slog.Uint64("requests_served", rs.served.Load()),
+		)
+	}
+
+	<-workersDone
+
+	if err := runHookWithRetry(ctx, "shutdown", cfg.OnShutdown, cfg.ShutdownHookRetries); err != nil {
+		slog.Error("shutdown hook failed, closing anyway", slog.String("error", err.Error()))
+	}
+
+	if cfg.Logger != nil {
+		// bounded like the rest, a blocked log file must not hang shutdown
+		if _, err := cfg.Logger.CloseContext(ctx); err != nil {
+			log.Printf("Error closing logger: %v", err)
+		}
+	}
+}
@@ -0,0 +1,49 @@
+package server
+
+import (
+	"bytes"
+	"log/slog"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestDefaultServerConfigShutdownTimeout(t *testing.T) {
+	tests := []struct {
+		name     string
+		value    string
+		want     time.Duration
+		wantWarn string
+	}{
+		{name: "valid", value: "15000", want: 15 * time.Second},
+		{name: "too small", value: "0", want: minShutdownTimeout, wantWarn: "SHUTDOWN_TIMEOUT_MS out of range, clamped"},
+		{name: "negative", value: "-500", want: minShutdownTimeout, wantWarn: "SHUTDOWN_TIMEOUT_MS out of range, clamped"},
+		{name: "too large", value: "3600000", want: maxShutdownTimeout, wantWarn: "SHUTDOWN_TIMEOUT_MS out of range, clamped"},
+		{name: "not a number", value: "soon", want: 5 * time.Second, wantWarn: "Invalid SHUTDOWN_TIMEOUT_MS"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var out bytes.Buffer
+			previous := slog.Default()
+			slog.SetDefault(slog.New(slog.NewTextHandler(&out, nil)))
+			defer slog.SetDefault(previous)
+
+			cfg := DefaultServerConfig(&Variables{port: ":0", shutdownTimeoutMS: tt.value})
+
+			if cfg.ShutdownTimeout != tt.want {
+				t.Errorf("ShutdownTimeout = %v, want %v", cfg.ShutdownTimeout, tt.want)
+			}
+
+			if tt.wantWarn == "" {
+				if out.Len() != 0 {
+					t.Errorf("unexpected warning: %s", out.String())
+				}
+				return
+			}
+			if !strings.Contains(out.String(), tt.wantWarn) {
+				t.Errorf("log = %q, want a %q warning", out.String(), tt.wantWarn)
+			}
+		})
+	}
+}
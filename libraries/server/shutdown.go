@@ -0,0 +1,137 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"os"
+	"os/signal"
+	"sync"
+	"syscall"
+	"time"
+)
+
+// ShutdownCoordinator lets background workers, e.g. queue consumers or cron
+// jobs, stop together with the process. Workers watch Context to stop taking
+// new work and register a cleanup that finishes the work in flight.
+type ShutdownCoordinator struct {
+	timeout time.Duration
+
+	ctx    context.Context
+	cancel context.CancelFunc
+
+	mu       sync.Mutex
+	cleanups map[string]func(ctx context.Context) error
+
+	once sync.Once
+	err  error
+}
+
+// NewShutdownCoordinator creates a new instance of ShutdownCoordinator. The
+// timeout bounds the cleanups when shutting down through Wait.
+func NewShutdownCoordinator(timeout time.Duration) *ShutdownCoordinator {
+	ctx, cancel := context.WithCancel(context.Background())
+
+	return &ShutdownCoordinator{
+		timeout:  timeout,
+		ctx:      ctx,
+		cancel:   cancel,
+		cleanups: make(map[string]func(ctx context.Context) error),
+	}
+}
+
+// Context is cancelled as soon as shutdown begins.
+func (c *ShutdownCoordinator) Context() context.Context {
+	return c.ctx
+}
+
+// Register adds a named cleanup run on shutdown with the shutdown deadline.
+// Registering the same name again replaces the cleanup.
+func (c *ShutdownCoordinator) Register(name string, cleanup func(ctx context.Context) error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.cleanups[name] = cleanup
+}
+
+// Shutdown cancels Context and runs every cleanup concurrently until they
+// return or ctx is done. Failures and panics are logged and returned joined.
+// Only the first call does the work, later ones return its result.
+func (c *ShutdownCoordinator) Shutdown(ctx context.Context) error {
+	c.once.Do(func() {
+		c.cancel()
+
+		c.mu.Lock()
+		cleanups := make(map[string]func(ctx context.Context) error, len(c.cleanups))
+		for name, cleanup := range c.cleanups {
+			cleanups[name] = cleanup
+		}
+		c.mu.Unlock()
+
+		var (
+			wg     sync.WaitGroup
+			errsMu sync.Mutex
+			errs   []error
+		)
+
+		for name, cleanup := range cleanups {
+			wg.Add(1)
+			go func(name string, cleanup func(ctx context.Context) error) {
+				defer wg.Done()
+
+				if err := runHook(ctx, name, cleanup); err != nil {
+					slog.Warn("shutdown cleanup failed", slog.String("worker", name), slog.String("error", err.Error()))
+
+					errsMu.Lock()
+					errs = append(errs, err)
+					errsMu.Unlock()
+				}
+			}(name, cleanup)
+		}
+
+		done := make(chan struct{})
+		go func() {
+			wg.Wait()
+			close(done)
+		}()
+
+		select {
+		case <-done:
+		case <-ctx.Done():
+			errsMu.Lock()
+			errs = append(errs, ctx.Err())
+			errsMu.Unlock()
+		}
+
+		errsMu.Lock()
+		c.err = errors.Join(errs...)
+		errsMu.Unlock()
+	})
+
+	return c.err
+}
+
+// Wait blocks until SIGINT or SIGTERM arrives or Shutdown got called, then
+// shuts down within the coordinator's timeout. It is meant for processes
+// running workers only, BuildAndStartServer waits on its coordinator the same
+// way but also drains the HTTP server.
+func (c *ShutdownCoordinator) Wait() error {
+	c.awaitStop()
+
+	ctx, cancel := context.WithTimeout(context.Background(), c.timeout)
+	defer cancel()
+
+	return c.Shutdown(ctx)
+}
+
+// awaitStop blocks until SIGINT or SIGTERM arrives or Context is cancelled.
+func (c *ShutdownCoordinator) awaitStop() {
+	stopChan := make(chan os.Signal, 1)
+	signal.Notify(stopChan, os.Interrupt, syscall.SIGTERM)
+	defer signal.Stop(stopChan)
+
+	select {
+	case <-stopChan:
+	case <-c.ctx.Done():
+	}
+}
@@ -0,0 +1,61 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestShutdownCoordinatorShutdown(t *testing.T) {
+	errCleanup := errors.New("cleanup failed")
+
+	tests := []struct {
+		name    string
+		cleanup func(ctx context.Context) error
+		wantErr error
+	}{
+		{name: "cleanup succeeds", cleanup: func(context.Context) error { return nil }},
+		{name: "cleanup fails", cleanup: func(context.Context) error { return errCleanup }, wantErr: errCleanup},
+		{
+			name:    "cleanup outlives the deadline",
+			cleanup: func(ctx context.Context) error { time.Sleep(time.Second); return nil },
+			wantErr: context.DeadlineExceeded,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := NewShutdownCoordinator(time.Second)
+			c.Register("worker", tt.cleanup)
+
+			ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+			defer cancel()
+
+			if err := c.Shutdown(ctx); !errors.Is(err, tt.wantErr) {
+				t.Errorf("Shutdown() = %v, want %v", err, tt.wantErr)
+			}
+			if c.Context().Err() == nil {
+				t.Error("Context not cancelled by Shutdown")
+			}
+		})
+	}
+}
+
+func TestShutdownCoordinatorAwaitStopReturnsOnShutdown(t *testing.T) {
+	c := NewShutdownCoordinator(time.Second)
+
+	done := make(chan struct{})
+	go func() {
+		c.awaitStop()
+		close(done)
+	}()
+
+	c.Shutdown(context.Background())
+
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("awaitStop did not return after Shutdown")
+	}
+}
@@ -0,0 +1,106 @@
+//go:build !windows
+
+package server
+
+import (
+	"bytes"
+	"context"
+	"log"
+	"log/slog"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"strings"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/go-chi/chi"
+	"github.com/himtar/go-boilerplate/pkg/logger"
+)
+
+func TestShutdownCoordinatorWaitOnSignal(t *testing.T) {
+	c := NewShutdownCoordinator(time.Second)
+
+	cleaned := make(chan struct{})
+	c.Register("worker", func(context.Context) error {
+		close(cleaned)
+		return nil
+	})
+
+	// keeps SIGTERM from killing the test binary before Wait listens
+	guard := make(chan os.Signal, 1)
+	signal.Notify(guard, syscall.SIGTERM)
+	defer signal.Stop(guard)
+
+	done := make(chan error, 1)
+	go func() { done <- c.Wait() }()
+
+	// Wait may not be listening yet, keep signalling until it returns
+	ticker := time.NewTicker(10 * time.Millisecond)
+	defer ticker.Stop()
+	timeout := time.After(2 * time.Second)
+
+	for {
+		select {
+		case err := <-done:
+			if err != nil {
+				t.Fatalf("Wait() = %v", err)
+			}
+			<-cleaned
+			return
+		case <-ticker.C:
+			syscall.Kill(syscall.Getpid(), syscall.SIGTERM)
+		case <-timeout:
+			t.Fatal("Wait did not return after SIGTERM")
+		}
+	}
+}
+
+func TestShutdownDoesNotHangOnBlockedLogFile(t *testing.T) {
+	// a FIFO nobody reads from stands in for a log file on a hung disk, writes
+	// block once the pipe buffer is full
+	path := filepath.Join(t.TempDir(), "app.log")
+	if err := syscall.Mkfifo(path, 0o644); err != nil {
+		t.Fatal(err)
+	}
+	reader, err := os.OpenFile(path, os.O_RDONLY|syscall.O_NONBLOCK, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// closing the reader fails the blocked write, ending the background flush
+	defer reader.Close()
+
+	l, err := logger.New(logger.Config{ServiceName: "orders", Format: "json", FilePath: path, FileBatchBytes: 1 << 20})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i := 0; i < 200; i++ {
+		l.Info("queued", slog.String("padding", strings.Repeat("x", 1024)))
+	}
+
+	var out bytes.Buffer
+	log.SetOutput(&out)
+	defer log.SetOutput(os.Stderr)
+
+	_, stop, err := StartForTest(context.Background(), &ServerConfig{Logger: l, ShutdownTimeout: 100 * time.Millisecond}, chi.NewRouter())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	stopped := make(chan struct{})
+	go func() {
+		stop()
+		close(stopped)
+	}()
+
+	select {
+	case <-stopped:
+	case <-time.After(5 * time.Second):
+		t.Fatal("shutdown hung on the blocked log file")
+	}
+
+	if !strings.Contains(out.String(), "200 entries unflushed") {
+		t.Errorf("log output = %q, want the unflushed entries reported", out.String())
+	}
+}
@@ -0,0 +1,79 @@
+package server
+
+import (
+	"bytes"
+	"net/http"
+
+	"golang.org/x/sync/singleflight"
+)
+
+// bufferedResponse collects a full response so it can be replayed to several callers.
+type bufferedResponse struct {
+	header http.Header
+	status int
+	body   bytes.Buffer
+}
+
+func (b *bufferedResponse) Header() http.Header {
+	return b.header
+}
+
+func (b *bufferedResponse) WriteHeader(status int) {
+	if b.status == 0 {
+		b.status = status
+	}
+}
+
+func (b *bufferedResponse) Write(p []byte) (int, error) {
+	if b.status == 0 {
+		b.status = http.StatusOK
+	}
+	return b.body.Write(p)
+}
+
+// defaultSingleFlightKey keys on the method, full URL and the caller's
+// credentials, so users never get each other's responses.
+func defaultSingleFlightKey(r *http.Request) string {
+	return r.Method + " " + r.URL.String() +
+		"\x00" + r.Header.Get("Authorization") +
+		"\x00" + r.Header.Get("Cookie")
+}
+
+// SingleFlightMiddleware lets concurrent identical GET and HEAD requests share
+// a single handler execution and its response. keyFn decides which requests
+// are identical, defaultSingleFlightKey when nil. The shared execution runs
+// with the first caller's context.
+func SingleFlightMiddleware(keyFn func(r *http.Request) string) func(http.Handler) http.Handler {
+	if keyFn == nil {
+		keyFn = defaultSingleFlightKey
+	}
+
+	var group singleflight.Group
+
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.Method != http.MethodGet && r.Method != http.MethodHead {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			result, _, _ := group.Do(keyFn(r), func() (interface{}, error) {
+				buf := &bufferedResponse{header: make(http.Header)}
+				next.ServeHTTP(buf, r)
+				return buf, nil
+			})
+
+			buf := result.(*bufferedResponse)
+			for name, values := range buf.header {
+				w.Header()[name] = append([]string(nil), values...)
+			}
+
+			status := buf.status
+			if status == 0 {
+				status = http.StatusOK
+			}
+			w.WriteHeader(status)
+			w.Write(buf.body.Bytes())
+		})
+	}
+}
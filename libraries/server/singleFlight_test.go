@@ -0,0 +1,66 @@
+package server
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestSingleFlightMiddleware(t *testing.T) {
+	tests := []struct {
+		name      string
+		method    string
+		authorize func(i int) string
+		wantCalls int32
+	}{
+		{name: "identical GETs share one execution", method: http.MethodGet, authorize: func(int) string { return "" }, wantCalls: 1},
+		{name: "users are never coalesced", method: http.MethodGet, authorize: func(i int) string { return "Bearer user-" + string(rune('a'+i%2)) }, wantCalls: 2},
+		{name: "POSTs are never coalesced", method: http.MethodPost, authorize: func(int) string { return "" }, wantCalls: 10},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var calls atomic.Int32
+			handler := SingleFlightMiddleware(nil)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				calls.Add(1)
+				// keep the execution open until every caller arrived
+				time.Sleep(100 * time.Millisecond)
+				w.Header().Set("X-Report", "ready")
+				w.WriteHeader(http.StatusAccepted)
+				w.Write([]byte("report"))
+			}))
+
+			const callers = 10
+			var wg sync.WaitGroup
+			recs := make([]*httptest.ResponseRecorder, callers)
+
+			for i := 0; i < callers; i++ {
+				wg.Add(1)
+				go func(i int) {
+					defer wg.Done()
+
+					req := httptest.NewRequest(tt.method, "/reports/1", nil)
+					if auth := tt.authorize(i); auth != "" {
+						req.Header.Set("Authorization", auth)
+					}
+					recs[i] = httptest.NewRecorder()
+					handler.ServeHTTP(recs[i], req)
+				}(i)
+			}
+			wg.Wait()
+
+			if got := calls.Load(); got != tt.wantCalls {
+				t.Errorf("handler calls = %d, want %d", got, tt.wantCalls)
+			}
+
+			for i, rec := range recs {
+				if rec.Code != http.StatusAccepted || rec.Body.String() != "report" || rec.Header().Get("X-Report") != "ready" {
+					t.Errorf("caller %d got %d %q %v", i, rec.Code, rec.Body.String(), rec.Header())
+				}
+			}
+		})
+	}
+}
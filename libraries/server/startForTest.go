@@ -0,0 +1,55 @@
+package server
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/go-chi/chi"
+)
+
+// testShutdownTimeout bounds StartForTest shutdowns when cfg sets no timeout.
+const testShutdownTimeout = 5 * time.Second
+
+// StartForTest runs app behind the full middleware stack on a free local
+// port, without env loading or signal handling, so tests can send real
+// requests and exercise the graceful shutdown. It returns the address
+// listened on and stop, which shuts down like a SIGTERM would. ctx being
+// done stops the server as well. cfg may be nil, its Addr is ignored.
+func StartForTest(ctx context.Context, cfg *ServerConfig, app *chi.Mux) (addr string, stop func(), err error) {
+	testCfg := ServerConfig{}
+	if cfg != nil {
+		testCfg = *cfg
+	}
+
+	testCfg.Addr = "127.0.0.1:0"
+	testCfg.StartedAt = time.Now()
+	if testCfg.ShutdownTimeout <= 0 {
+		testCfg.ShutdownTimeout = testShutdownTimeout
+	}
+
+	if err := runHook(ctx, "startup", testCfg.OnStartup); err != nil {
+		return "", nil, err
+	}
+
+	if testCfg.Middlewares == nil {
+		testCfg.Middlewares = BuildDefaultMiddlewares(&Variables{env: "test"}, testCfg.Logger)
+	}
+
+	rs, err := startServer(&testCfg, app, false)
+	if err != nil {
+		return "", nil, err
+	}
+
+	var once sync.Once
+	shutdown := func() { once.Do(rs.shutdown) }
+
+	// unregistered on stop, a ctx that is never done must not pin the server
+	stopOnDone := context.AfterFunc(ctx, shutdown)
+	stop = func() {
+		stopOnDone()
+		shutdown()
+	}
+
+	return rs.listener.Addr().String(), stop, nil
+}
@@ -0,0 +1,314 @@
+package server
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"log/slog"
+	"net"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/go-chi/chi"
+	"github.com/himtar/go-boilerplate/pkg/logger"
+)
+
+func TestShutdownLogsUptime(t *testing.T) {
+	var out bytes.Buffer
+	previous := slog.Default()
+	slog.SetDefault(slog.New(slog.NewJSONHandler(&out, nil)))
+	defer slog.SetDefault(previous)
+
+	app := chi.NewRouter()
+	app.Get("/ping", func(w http.ResponseWriter, r *http.Request) {})
+
+	addr, stop, err := StartForTest(context.Background(), &ServerConfig{Logger: logger.DiscardLogger()}, app)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	res, err := http.Get("http://" + addr + "/ping")
+	if err != nil {
+		t.Fatal(err)
+	}
+	res.Body.Close()
+
+	stop()
+
+	var stopped map[string]interface{}
+	decoder := json.NewDecoder(&out)
+	for decoder.More() {
+		var entry map[string]interface{}
+		if err := decoder.Decode(&entry); err != nil {
+			t.Fatal(err)
+		}
+		if entry["msg"] == "server stopped gracefully" {
+			stopped = entry
+		}
+	}
+
+	if stopped == nil {
+		t.Fatalf("no shutdown entry logged: %s", out.String())
+	}
+
+	uptime, err := time.ParseDuration(stopped["uptime"].(string))
+	if err != nil || uptime < 0 || uptime > time.Minute {
+		t.Errorf("uptime = %v, want a plausible duration", stopped["uptime"])
+	}
+	if stopped["requests_served"] != 1.0 {
+		t.Errorf("requests_served = %v, want 1", stopped["requests_served"])
+	}
+}
+
+func TestShutdownFlushesLogger(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "app.log")
+	appLogger, err := logger.New(logger.Config{
+		ServiceName:    "orders",
+		Format:         "json",
+		FilePath:       path,
+		FileBatchBytes: 1 << 20,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	_, stop, err := StartForTest(context.Background(), &ServerConfig{Logger: appLogger}, chi.NewRouter())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	appLogger.Info("last words before shutdown")
+
+	written, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(string(written), "last words before shutdown") {
+		t.Fatal("entry written before shutdown, the batch did not buffer it")
+	}
+
+	stop()
+
+	written, err = os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(written), "last words before shutdown") {
+		t.Errorf("entry lost on shutdown, file holds %q", written)
+	}
+}
+
+func TestMaxConnections(t *testing.T) {
+	slowStarted := make(chan struct{})
+	slowReturned := make(chan struct{})
+	release := make(chan struct{})
+	var servedEarly atomic.Bool
+
+	app := chi.NewRouter()
+	app.Get("/slow", func(w http.ResponseWriter, r *http.Request) {
+		defer close(slowReturned)
+		close(slowStarted)
+		<-release
+	})
+	fastServed := make(chan struct{})
+	app.Get("/fast", func(w http.ResponseWriter, r *http.Request) {
+		defer close(fastServed)
+		// the only slot frees once the slow connection closed, after its handler returned
+		select {
+		case <-slowReturned:
+		default:
+			servedEarly.Store(true)
+		}
+	})
+
+	addr, stop, err := StartForTest(context.Background(), &ServerConfig{Logger: logger.DiscardLogger(), MaxConnections: 1}, app)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer stop()
+
+	slowClient := &http.Client{Transport: &http.Transport{DisableKeepAlives: true}}
+
+	slowDone := make(chan error, 1)
+	go func() {
+		res, err := slowClient.Get("http://" + addr + "/slow")
+		if err == nil {
+			res.Body.Close()
+		}
+		slowDone <- err
+	}()
+	<-slowStarted
+
+	// the kernel completes the connect even while the server holds off on Accept
+	fastDialed := make(chan struct{})
+	var dialer net.Dialer
+	fastClient := &http.Client{Transport: &http.Transport{
+		DisableKeepAlives: true,
+		DialContext: func(ctx context.Context, network, addr string) (net.Conn, error) {
+			conn, err := dialer.DialContext(ctx, network, addr)
+			close(fastDialed)
+			return conn, err
+		},
+	}}
+
+	fastDone := make(chan error, 1)
+	go func() {
+		res, err := fastClient.Get("http://" + addr + "/fast")
+		if err == nil {
+			res.Body.Close()
+		}
+		fastDone <- err
+	}()
+	<-fastDialed
+
+	// servedEarly decides the outcome, this only gives a broken limit the chance
+	// to serve the fast request while the slot is still taken
+	select {
+	case <-fastServed:
+	case <-time.After(100 * time.Millisecond):
+	}
+	close(release)
+
+	for name, done := range map[string]chan error{"slow": slowDone, "fast": fastDone} {
+		select {
+		case err := <-done:
+			if err != nil {
+				t.Errorf("%s request failed: %v", name, err)
+			}
+		case <-time.After(2 * time.Second):
+			t.Errorf("%s request never completed", name)
+		}
+	}
+
+	if servedEarly.Load() {
+		t.Error("connection over the limit served while the slot was taken")
+	}
+}
+
+func TestLargeHeaders(t *testing.T) {
+	tests := []struct {
+		name              string
+		maxHeaderBytes    int
+		allowLargeHeaders bool
+		headerBytes       int
+		wantStatus        int
+	}{
+		{name: "default cap accepts normal headers", headerBytes: 4 << 10, wantStatus: http.StatusOK},
+		{name: "default cap rejects just over 1MB", headerBytes: 1<<20 + 64<<10, wantStatus: http.StatusRequestHeaderFieldsTooLarge},
+		{name: "large headers allowed", allowLargeHeaders: true, headerBytes: 1<<20 + 64<<10, wantStatus: http.StatusOK},
+		{name: "custom cap", maxHeaderBytes: 16 << 10, headerBytes: 32 << 10, wantStatus: http.StatusRequestHeaderFieldsTooLarge},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			app := chi.NewRouter()
+			app.Get("/ping", func(w http.ResponseWriter, r *http.Request) {})
+
+			cfg := &ServerConfig{
+				Logger:            logger.DiscardLogger(),
+				MaxHeaderBytes:    tt.maxHeaderBytes,
+				AllowLargeHeaders: tt.allowLargeHeaders,
+			}
+			addr, stop, err := StartForTest(context.Background(), cfg, app)
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer stop()
+
+			req, err := http.NewRequest(http.MethodGet, "http://"+addr+"/ping", nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+			req.Header.Set("Authorization", "Bearer "+strings.Repeat("x", tt.headerBytes))
+
+			res, err := http.DefaultClient.Do(req)
+			if err != nil {
+				t.Fatal(err)
+			}
+			res.Body.Close()
+
+			if res.StatusCode != tt.wantStatus {
+				t.Errorf("status = %d, want %d", res.StatusCode, tt.wantStatus)
+			}
+		})
+	}
+}
+
+func TestStartForTestDrainsInFlightRequests(t *testing.T) {
+	started := make(chan struct{})
+	app := chi.NewRouter()
+	app.Get("/slow", func(w http.ResponseWriter, r *http.Request) {
+		close(started)
+		time.Sleep(50 * time.Millisecond)
+		w.Write([]byte("done"))
+	})
+
+	addr, stop, err := StartForTest(context.Background(), &ServerConfig{Logger: logger.DiscardLogger()}, app)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	type result struct {
+		body string
+		err  error
+	}
+	results := make(chan result, 1)
+	go func() {
+		res, err := http.Get("http://" + addr + "/slow")
+		if err != nil {
+			results <- result{err: err}
+			return
+		}
+		defer res.Body.Close()
+
+		var body bytes.Buffer
+		_, err = body.ReadFrom(res.Body)
+		results <- result{body: body.String(), err: err}
+	}()
+
+	<-started
+	stop()
+	// a second stop is a no-op
+	stop()
+
+	got := <-results
+	if got.err != nil || got.body != "done" {
+		t.Errorf("in-flight request = %q, %v, want it to complete", got.body, got.err)
+	}
+
+	if _, err := http.Get("http://" + addr + "/slow"); err == nil {
+		t.Error("server still accepts requests after stop")
+	}
+}
+
+func TestStartForTestStopsOnContextDone(t *testing.T) {
+	shutdownRan := make(chan struct{})
+	cfg := &ServerConfig{
+		Logger: logger.DiscardLogger(),
+		OnShutdown: func(ctx context.Context) error {
+			close(shutdownRan)
+			return nil
+		},
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	addr, _, err := StartForTest(ctx, cfg, chi.NewRouter())
+	if err != nil {
+		t.Fatal(err)
+	}
+	cancel()
+
+	select {
+	case <-shutdownRan:
+	case <-time.After(testShutdownTimeout):
+		t.Fatal("server did not shut down when ctx was cancelled")
+	}
+	if _, err := http.Get("http://" + addr + LivenessPath); err == nil {
+		t.Error("server still accepts requests after ctx was cancelled")
+	}
+}
@@ -0,0 +1,24 @@
+package server
+
+import (
+	"context"
+	"net/http"
+	"time"
+)
+
+const startTimeKey contextKey = "start_time"
+
+// StartTimeMiddleware records when the request was received so every later
+// middleware and handler measures from the same instant. Register it first.
+func StartTimeMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ctx := context.WithValue(r.Context(), startTimeKey, time.Now())
+		next.ServeHTTP(w, r.WithContext(ctx))
+	})
+}
+
+// RequestStartTime returns the time stored by StartTimeMiddleware, the zero time if absent.
+func RequestStartTime(ctx context.Context) time.Time {
+	start, _ := ctx.Value(startTimeKey).(time.Time)
+	return start
+}
@@ -0,0 +1,32 @@
+package server
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestRequestStartTime(t *testing.T) {
+	var inner, outer time.Time
+	handler := StartTimeMiddleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		time.Sleep(50 * time.Millisecond)
+		inner = RequestStartTime(r.Context())
+	}))
+	handler = func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			outer = RequestStartTime(r.Context())
+			next.ServeHTTP(w, r)
+		})
+	}(handler)
+
+	received := time.Now()
+	handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/", nil))
+
+	if !outer.IsZero() {
+		t.Errorf("start time visible before the middleware ran: %v", outer)
+	}
+	if inner.Before(received) || inner.Sub(received) >= 50*time.Millisecond {
+		t.Errorf("start time = %v, want close to %v", inner, received)
+	}
+}
@@ -0,0 +1,15 @@
+package server
+
+import (
+	"github.com/himtar/go-boilerplate/pkg/router"
+)
+
+// ServeStaticAsset serves content at path for GET and HEAD, e.g. with
+// router.FaviconHandler and router.RobotsHandler content, so browsers and
+// crawlers stop producing 404s.
+func (r *HTTPRouter) ServeStaticAsset(path string, content []byte, contentType string) {
+	handler := router.StaticAsset(content, contentType)
+
+	r.Get(path, handler)
+	r.Head(path, handler)
+}
@@ -0,0 +1,57 @@
+package server
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/go-chi/chi"
+)
+
+func TestHTTPRouterServeStaticAsset(t *testing.T) {
+	r, err := NewHTTPRouter(chi.NewRouter(), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	notFound := 0
+	r.NotFound(func(w http.ResponseWriter, req *http.Request) {
+		notFound++
+		w.WriteHeader(http.StatusNotFound)
+	})
+
+	r.ServeStaticAsset("/favicon.ico", []byte("icon"), "image/x-icon")
+	r.ServeStaticAsset("/robots.txt", []byte("User-agent: *\n"), "text/plain; charset=utf-8")
+
+	tests := []struct {
+		method          string
+		path            string
+		wantContentType string
+		wantBody        string
+	}{
+		{method: http.MethodGet, path: "/favicon.ico", wantContentType: "image/x-icon", wantBody: "icon"},
+		{method: http.MethodGet, path: "/robots.txt", wantContentType: "text/plain; charset=utf-8", wantBody: "User-agent: *\n"},
+		{method: http.MethodHead, path: "/robots.txt", wantContentType: "text/plain; charset=utf-8"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.method+" "+tt.path, func(t *testing.T) {
+			rec := httptest.NewRecorder()
+			r.ServeHTTP(rec, httptest.NewRequest(tt.method, tt.path, nil))
+
+			if rec.Code != http.StatusOK {
+				t.Fatalf("status = %d, want %d", rec.Code, http.StatusOK)
+			}
+			if got := rec.Header().Get("Content-Type"); got != tt.wantContentType {
+				t.Errorf("Content-Type = %q, want %q", got, tt.wantContentType)
+			}
+			if rec.Body.String() != tt.wantBody {
+				t.Errorf("body = %q, want %q", rec.Body.String(), tt.wantBody)
+			}
+		})
+	}
+
+	if notFound != 0 {
+		t.Errorf("NotFound handler hit %d times", notFound)
+	}
+}
@@ -0,0 +1,18 @@
+package server
+
+import "net/http"
+
+// StripHeadersMiddleware removes the given headers from incoming requests
+// before they reach handlers, e.g. a client supplied X-Real-IP when the
+// service isn't behind a proxy that sets it.
+func StripHeadersMiddleware(headers ...string) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			for _, name := range headers {
+				r.Header.Del(name)
+			}
+
+			next.ServeHTTP(w, r)
+		})
+	}
+}
@@ -0,0 +1,38 @@
+package server
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestStripHeadersMiddleware(t *testing.T) {
+	var got http.Header
+	handler := StripHeadersMiddleware("X-Real-IP", "x-forwarded-for")(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		got = r.Header.Clone()
+	}))
+
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req.Header.Set("X-Real-IP", "10.0.0.1")
+	req.Header.Add("X-Forwarded-For", "10.0.0.2")
+	req.Header.Add("X-Forwarded-For", "10.0.0.3")
+	req.Header.Set("Authorization", "Bearer token")
+	handler.ServeHTTP(httptest.NewRecorder(), req)
+
+	tests := []struct {
+		header string
+		want   string
+	}{
+		{header: "X-Real-IP", want: ""},
+		{header: "X-Forwarded-For", want: ""},
+		{header: "Authorization", want: "Bearer token"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.header, func(t *testing.T) {
+			if value := got.Get(tt.header); value != tt.want {
+				t.Errorf("%s = %q, want %q", tt.header, value, tt.want)
+			}
+		})
+	}
+}
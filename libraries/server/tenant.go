@@ -0,0 +1,31 @@
+package server
+
+import (
+	"net/http"
+
+	"github.com/himtar/go-boilerplate/pkg/logger"
+)
+
+// TenantHeader is the default header TenantLoggerMiddleware reads the tenant from.
+const TenantHeader = "X-Tenant-ID"
+
+// TenantLoggerMiddleware attaches a logger scoped to the request's tenant,
+// read from header (TenantHeader when empty), to the request context.
+// Handlers get it through logger.FromContext. Requests without a tenant get
+// base as is.
+func TenantLoggerMiddleware(base *logger.Logger, header string) func(http.Handler) http.Handler {
+	if header == "" {
+		header = TenantHeader
+	}
+
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			l := base
+			if tenantID := r.Header.Get(header); tenantID != "" {
+				l = base.ForTenant(tenantID)
+			}
+
+			next.ServeHTTP(w, r.WithContext(logger.NewContext(r.Context(), l)))
+		})
+	}
+}
@@ -0,0 +1,68 @@
+package server
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/himtar/go-boilerplate/pkg/logger"
+)
+
+func TestTenantLoggerMiddleware(t *testing.T) {
+	tests := []struct {
+		name       string
+		header     string
+		sendHeader string
+		tenant     string
+		wantTenant interface{}
+	}{
+		{name: "default header", sendHeader: TenantHeader, tenant: "acme", wantTenant: "acme"},
+		{name: "custom header", header: "X-Org", sendHeader: "X-Org", tenant: "globex", wantTenant: "globex"},
+		{name: "no tenant", sendHeader: TenantHeader},
+		{name: "tenant in another header", header: "X-Org", sendHeader: TenantHeader, tenant: "acme"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "app.log")
+			var audit bytes.Buffer
+			base, err := logger.New(logger.Config{ServiceName: "orders", Format: "json", FilePath: path, AuditWriter: &audit})
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			handler := TenantLoggerMiddleware(base, tt.header)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				l := logger.FromContext(r.Context())
+				l.InfoContext(r.Context(), "order created")
+				l.Audit(r.Context(), "order.create", nil)
+			}))
+
+			req := httptest.NewRequest(http.MethodPost, "/orders", nil)
+			if tt.tenant != "" {
+				req.Header.Set(tt.sendHeader, tt.tenant)
+			}
+			handler.ServeHTTP(httptest.NewRecorder(), req)
+
+			if err := base.Close(); err != nil {
+				t.Fatal(err)
+			}
+			written, err := os.ReadFile(path)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			for stream, buf := range map[string]*bytes.Buffer{"main": bytes.NewBuffer(written), "audit": &audit} {
+				entries := logEntries(t, buf)
+				if len(entries) != 1 {
+					t.Fatalf("%s: got %d entries, want 1", stream, len(entries))
+				}
+				if got := entries[0]["tenant_id"]; got != tt.wantTenant {
+					t.Errorf("%s: tenant_id = %v, want %v", stream, got, tt.wantTenant)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,61 @@
+package server
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"net/http"
+
+	"github.com/himtar/go-boilerplate/pkg/response"
+	"github.com/himtar/go-boilerplate/pkg/trace"
+)
+
+type contextKey string
+
+// TraceIDHeader carries the trace ID in both directions.
+const TraceIDHeader = trace.Header
+
+// IDGenerator produces trace and request IDs, e.g. ULIDs or a seeded sequence in tests.
+type IDGenerator func() string
+
+// newTraceID returns a random 128 bit hex encoded ID.
+func newTraceID() string {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return ""
+	}
+	return hex.EncodeToString(b)
+}
+
+// TraceIDMiddleware reuses the incoming X-Trace-ID or generates one, stores it
+// in the request context, echoes it back in the response header and makes it
+// available to error responses sent through the response package.
+func TraceIDMiddleware(next http.Handler) http.Handler {
+	return NewTraceIDMiddleware(nil)(next)
+}
+
+// NewTraceIDMiddleware is TraceIDMiddleware with a custom ID generator, random hex when nil.
+func NewTraceIDMiddleware(generate IDGenerator) func(http.Handler) http.Handler {
+	if generate == nil {
+		generate = newTraceID
+	}
+
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			traceID := r.Header.Get(TraceIDHeader)
+			if traceID == "" {
+				traceID = generate()
+			}
+
+			w.Header().Set(TraceIDHeader, traceID)
+			ctx := trace.NewContext(r.Context(), traceID)
+
+			next.ServeHTTP(response.WithTraceID(w, traceID), r.WithContext(ctx))
+		})
+	}
+}
+
+// TraceID returns the trace ID stored by TraceIDMiddleware, "" if absent.
+func TraceID(ctx context.Context) string {
+	return trace.ID(ctx)
+}
@@ -0,0 +1,85 @@
+package server
+
+import (
+	"compress/gzip"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/himtar/go-boilerplate/pkg/logger"
+	"github.com/himtar/go-boilerplate/pkg/response"
+)
+
+func TestErrorResponsesCarryTraceID(t *testing.T) {
+	tests := []struct {
+		name  string
+		inner []func(http.Handler) http.Handler
+	}{
+		{name: "directly"},
+		{name: "through compression", inner: []func(http.Handler) http.Handler{CompressMiddleware(CompressDefault)}},
+		{name: "through idempotency", inner: []func(http.Handler) http.Handler{IdempotencyMiddleware(nil)}},
+		{name: "through the recorder", inner: []func(http.Handler) http.Handler{LoggerMiddleware(LoggerOptions{Logger: logger.DiscardLogger().Logger})}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var handler http.Handler = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				response.Send(w, http.StatusInternalServerError, "boom", nil)
+			})
+			for i := len(tt.inner) - 1; i >= 0; i-- {
+				handler = tt.inner[i](handler)
+			}
+			handler = TraceIDMiddleware(handler)
+
+			req := httptest.NewRequest(http.MethodPost, "/orders", nil)
+			req.Header.Set(TraceIDHeader, "trace-123")
+			req.Header.Set(IdempotencyKeyHeader, "key")
+			req.Header.Set("Accept-Encoding", "gzip")
+			rec := httptest.NewRecorder()
+			handler.ServeHTTP(rec, req)
+
+			var body io.Reader = rec.Body
+			if rec.Header().Get("Content-Encoding") == "gzip" {
+				gz, err := gzip.NewReader(rec.Body)
+				if err != nil {
+					t.Fatal(err)
+				}
+				body = gz
+			}
+
+			var res response.Response
+			if err := json.NewDecoder(body).Decode(&res); err != nil {
+				t.Fatal(err)
+			}
+
+			if rec.Code != http.StatusInternalServerError {
+				t.Errorf("status = %d, want %d", rec.Code, http.StatusInternalServerError)
+			}
+			if res.TraceID != "trace-123" {
+				t.Errorf("trace_id = %q, want %q", res.TraceID, "trace-123")
+			}
+		})
+	}
+}
+
+func TestSuccessResponsesOmitTraceID(t *testing.T) {
+	handler := TraceIDMiddleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		response.Send(w, http.StatusOK, "", map[string]int{"count": 1})
+	}))
+
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/", nil))
+
+	var res response.Response
+	if err := json.Unmarshal(rec.Body.Bytes(), &res); err != nil {
+		t.Fatal(err)
+	}
+	if res.TraceID != "" {
+		t.Errorf("trace_id = %q on a success response", res.TraceID)
+	}
+	if rec.Header().Get(TraceIDHeader) == "" {
+		t.Error("no generated trace ID header")
+	}
+}
@@ -0,0 +1,61 @@
+package server
+
+import (
+	"context"
+	"net/http"
+	"sync"
+	"time"
+)
+
+const tracerKey contextKey = "tracer"
+
+// RequestTracer records how long named steps of a request took.
+type RequestTracer struct {
+	mu    sync.Mutex
+	steps map[string]time.Duration
+}
+
+// Step starts timing name and returns the func that stops it, meant for
+// `defer server.Tracer(ctx).Step("db")()`. Repeated steps add up.
+func (t *RequestTracer) Step(name string) func() {
+	if t == nil {
+		return func() {}
+	}
+
+	start := time.Now()
+	return func() {
+		t.mu.Lock()
+		defer t.mu.Unlock()
+
+		t.steps[name] += time.Since(start)
+	}
+}
+
+// Steps returns a copy of the recorded step durations.
+func (t *RequestTracer) Steps() map[string]time.Duration {
+	if t == nil {
+		return nil
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	steps := make(map[string]time.Duration, len(t.steps))
+	for name, d := range t.steps {
+		steps[name] = d
+	}
+	return steps
+}
+
+// Tracer returns the request's tracer. It is nil, and Step a no-op, outside
+// requests served through LoggerMiddleware.
+func Tracer(ctx context.Context) *RequestTracer {
+	tracer, _ := ctx.Value(tracerKey).(*RequestTracer)
+	return tracer
+}
+
+// withTracer attaches a fresh RequestTracer to the request.
+func withTracer(r *http.Request) (*http.Request, *RequestTracer) {
+	tracer := &RequestTracer{steps: make(map[string]time.Duration)}
+	return r.WithContext(context.WithValue(r.Context(), tracerKey, tracer)), tracer
+}
@@ -0,0 +1,97 @@
+package server
+
+import (
+	"bytes"
+	"context"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestTracerStepsLogged(t *testing.T) {
+	var out bytes.Buffer
+	opts := LoggerOptions{Logger: slog.New(slog.NewJSONHandler(&out, nil))}
+
+	handler := LoggerMiddleware(opts)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		tracer := Tracer(r.Context())
+
+		stop := tracer.Step("db")
+		time.Sleep(20 * time.Millisecond)
+		stop()
+
+		stop = tracer.Step("render")
+		time.Sleep(5 * time.Millisecond)
+		stop()
+	}))
+	handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/orders", nil))
+
+	entries := logEntries(t, &out)
+	if len(entries) != 1 {
+		t.Fatalf("got %d entries, want 1", len(entries))
+	}
+
+	steps, ok := entries[0]["steps"].(map[string]interface{})
+	if !ok {
+		t.Fatalf("no steps in the log entry: %v", entries[0])
+	}
+
+	tests := []struct {
+		step string
+		min  time.Duration
+	}{
+		{step: "db", min: 20 * time.Millisecond},
+		{step: "render", min: 5 * time.Millisecond},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.step, func(t *testing.T) {
+			ns, ok := steps[tt.step].(float64)
+			if !ok {
+				t.Fatalf("step %q missing: %v", tt.step, steps)
+			}
+			if got := time.Duration(ns); got < tt.min || got > tt.min+time.Second {
+				t.Errorf("%s took %v, want at least %v", tt.step, got, tt.min)
+			}
+		})
+	}
+}
+
+func TestTracerRepeatedStepsAddUp(t *testing.T) {
+	_, tracer := withTracer(httptest.NewRequest(http.MethodGet, "/", nil))
+
+	for i := 0; i < 3; i++ {
+		stop := tracer.Step("query")
+		time.Sleep(2 * time.Millisecond)
+		stop()
+	}
+
+	if got := tracer.Steps()["query"]; got < 6*time.Millisecond {
+		t.Errorf("query = %v, want the sum of all three steps", got)
+	}
+}
+
+func TestTracerOutsideRequest(t *testing.T) {
+	tracer := Tracer(context.Background())
+	if tracer != nil {
+		t.Fatalf("Tracer() = %v, want nil outside LoggerMiddleware", tracer)
+	}
+
+	tracer.Step("db")()
+	if steps := tracer.Steps(); steps != nil {
+		t.Errorf("Steps() = %v, want nil", steps)
+	}
+}
+
+func TestLoggerMiddlewareOmitsEmptySteps(t *testing.T) {
+	var out bytes.Buffer
+	opts := LoggerOptions{Logger: slog.New(slog.NewJSONHandler(&out, nil))}
+
+	LoggerMiddleware(opts)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {})).
+		ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/", nil))
+
+	if _, ok := logEntries(t, &out)[0]["steps"]; ok {
+		t.Error("steps logged for a request without any")
+	}
+}
@@ -0,0 +1,31 @@
+package server
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/go-chi/chi"
+)
+
+// URLParam returns the named path parameter of req, e.g. id for /users/{id},
+// or "" when the route has none. chi keeps parameters in the request context,
+// so routes registered in nested Route or Group calls work the same.
+func (r *HTTPRouter) URLParam(req *http.Request, name string) string {
+	return chi.URLParam(req, name)
+}
+
+// URLParamInt returns the named path parameter as an int, failing when it is
+// missing or not a number.
+func (r *HTTPRouter) URLParamInt(req *http.Request, name string) (int, error) {
+	value := r.URLParam(req, name)
+	if value == "" {
+		return 0, fmt.Errorf("missing url param %q", name)
+	}
+
+	n, err := strconv.Atoi(value)
+	if err != nil {
+		return 0, fmt.Errorf("url param %q is not a number: %q", name, value)
+	}
+	return n, nil
+}
@@ -0,0 +1,74 @@
+package server
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/go-chi/chi"
+)
+
+func TestURLParamNestedRoutes(t *testing.T) {
+	var id, missing string
+	var n int
+	var intErr error
+
+	app, err := NewHTTPRouter(chi.NewRouter(), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	app.Route("/users", func(r *HTTPRouter) {
+		r.Get("/{id}", func(w http.ResponseWriter, req *http.Request) {
+			id = r.URLParam(req, "id")
+			missing = r.URLParam(req, "order")
+			n, intErr = r.URLParamInt(req, "id")
+		})
+	})
+
+	tests := []struct {
+		name       string
+		path       string
+		wantID     string
+		wantInt    int
+		wantIntErr bool
+	}{
+		{name: "numeric id", path: "/users/42", wantID: "42", wantInt: 42},
+		{name: "non numeric id", path: "/users/alice", wantID: "alice", wantIntErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := httptest.NewRecorder()
+			app.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, tt.path, nil))
+
+			if rec.Code != http.StatusOK {
+				t.Fatalf("status = %d, want %d", rec.Code, http.StatusOK)
+			}
+			if id != tt.wantID {
+				t.Errorf("URLParam(id) = %q, want %q", id, tt.wantID)
+			}
+			if missing != "" {
+				t.Errorf("URLParam(order) = %q, want empty", missing)
+			}
+			if (intErr != nil) != tt.wantIntErr {
+				t.Errorf("URLParamInt() err = %v, wantErr %v", intErr, tt.wantIntErr)
+			}
+			if n != tt.wantInt {
+				t.Errorf("URLParamInt() = %d, want %d", n, tt.wantInt)
+			}
+		})
+	}
+}
+
+func TestURLParamIntMissing(t *testing.T) {
+	r, err := NewHTTPRouter(chi.NewRouter(), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	req := httptest.NewRequest(http.MethodGet, "/users", nil)
+	if _, err := r.URLParamInt(req, "id"); err == nil {
+		t.Error("URLParamInt() accepted a missing param")
+	}
+}
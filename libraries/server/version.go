@@ -0,0 +1,36 @@
+package server
+
+import (
+	"net/http"
+
+	"github.com/himtar/go-boilerplate/pkg/response"
+)
+
+// VersionPath is where BuildAndStartServer serves the build info.
+const VersionPath = "/version"
+
+// Commit and BuildTime describe the build, set at build time like Version with
+// -ldflags "-X github.com/himtar/go-boilerplate/libraries/server.Commit=$(git rev-parse HEAD)".
+var (
+	Commit    = ""
+	BuildTime = ""
+)
+
+// VersionInfo identifies the deployed build.
+type VersionInfo struct {
+	Version   string `json:"version"`
+	Commit    string `json:"commit"`
+	BuildTime string `json:"build_time"`
+}
+
+// BuildInfo returns the version info injected at build time.
+func BuildInfo() VersionInfo {
+	return VersionInfo{Version: Version, Commit: Commit, BuildTime: BuildTime}
+}
+
+// VersionHandler serves info as JSON, to confirm which build is deployed.
+func VersionHandler(info VersionInfo) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		response.SendJSON(w, http.StatusOK, info)
+	}
+}
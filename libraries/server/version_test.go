@@ -0,0 +1,63 @@
+package server
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+
+	"github.com/go-chi/chi"
+)
+
+func TestVersionHandler(t *testing.T) {
+	tests := []struct {
+		name string
+		info VersionInfo
+	}{
+		{name: "full build info", info: VersionInfo{Version: "v1.2.3", Commit: "4ac9e52", BuildTime: "2026-10-16T18:50:50Z"}},
+		{name: "unset build info", info: VersionInfo{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := httptest.NewRecorder()
+			VersionHandler(tt.info)(rec, httptest.NewRequest(http.MethodGet, VersionPath, nil))
+
+			if rec.Code != http.StatusOK {
+				t.Fatalf("status = %d, want %d", rec.Code, http.StatusOK)
+			}
+
+			var got VersionInfo
+			if err := json.Unmarshal(rec.Body.Bytes(), &got); err != nil {
+				t.Fatal(err)
+			}
+			if got != tt.info {
+				t.Errorf("version info = %+v, want %+v", got, tt.info)
+			}
+		})
+	}
+}
+
+func TestVersionPathServesBuildInfo(t *testing.T) {
+	previous := [3]string{Version, Commit, BuildTime}
+	Version, Commit, BuildTime = "v1.2.3", "4ac9e52", "2026-10-16T18:50:50Z"
+	defer func() { Version, Commit, BuildTime = previous[0], previous[1], previous[2] }()
+
+	var served atomic.Uint64
+	handler, err := prepareServer(chi.NewRouter(), &served, false, nil, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, VersionPath, nil))
+
+	var got VersionInfo
+	if err := json.Unmarshal(rec.Body.Bytes(), &got); err != nil {
+		t.Fatalf("decoding %q: %v", rec.Body.String(), err)
+	}
+	if want := BuildInfo(); got != want {
+		t.Errorf("version info = %+v, want %+v", got, want)
+	}
+}
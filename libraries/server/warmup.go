@@ -0,0 +1,54 @@
+package server
+
+import (
+	"context"
+	"log/slog"
+	"sync/atomic"
+	"time"
+)
+
+// defaultWarmupRetryInterval is the pause between failed warm-up attempts.
+const defaultWarmupRetryInterval = 5 * time.Second
+
+// warmingUp holds readiness at 503 until the warm-up hook succeeded.
+var warmingUp atomic.Bool
+
+// IsWarmingUp reports whether the warm-up hook is still running or retrying.
+func IsWarmingUp() bool {
+	return warmingUp.Load()
+}
+
+// runWarmup calls hook until it succeeds or ctx is done, waiting interval
+// between attempts, and marks the server warm once it passed.
+func runWarmup(ctx context.Context, hook func(ctx context.Context) error, interval time.Duration) {
+	if hook == nil {
+		return
+	}
+
+	if interval <= 0 {
+		interval = defaultWarmupRetryInterval
+	}
+
+	warmingUp.Store(true)
+
+	for attempt := 1; ; attempt++ {
+		err := runHook(ctx, "warmup", hook)
+		if err == nil {
+			warmingUp.Store(false)
+			slog.Info("warm-up done, server ready", slog.Int("attempts", attempt))
+			return
+		}
+
+		slog.Warn("warm-up failed, retrying",
+			slog.Int("attempt", attempt),
+			slog.Duration("retry_in", interval),
+			slog.String("error", err.Error()),
+		)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(interval):
+		}
+	}
+}
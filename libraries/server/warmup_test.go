@@ -0,0 +1,92 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/go-chi/chi"
+	"github.com/himtar/go-boilerplate/pkg/logger"
+)
+
+func TestWarmupHoldsReadiness(t *testing.T) {
+	t.Cleanup(func() { warmingUp.Store(false) })
+
+	var attempts atomic.Int32
+	proceed := make(chan struct{})
+	cfg := &ServerConfig{
+		Logger:              logger.DiscardLogger(),
+		WarmupRetryInterval: 10 * time.Millisecond,
+		OnWarmup: func(ctx context.Context) error {
+			// the first attempt fails, the retry waits for the test to let it pass
+			if attempts.Add(1) == 1 {
+				return errors.New("cache not reachable")
+			}
+			<-proceed
+			return nil
+		},
+	}
+
+	addr, stop, err := StartForTest(context.Background(), cfg, chi.NewRouter())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer stop()
+
+	readiness := func() int {
+		t.Helper()
+		res, err := http.Get("http://" + addr + ReadinessPath)
+		if err != nil {
+			t.Fatal(err)
+		}
+		res.Body.Close()
+		return res.StatusCode
+	}
+
+	if status := readiness(); status != http.StatusServiceUnavailable {
+		t.Errorf("readiness while warming up = %d, want %d", status, http.StatusServiceUnavailable)
+	}
+
+	// wait for the retry, readiness has to stay down through the failure
+	for attempts.Load() < 2 {
+		time.Sleep(time.Millisecond)
+	}
+	if status := readiness(); status != http.StatusServiceUnavailable {
+		t.Errorf("readiness after a failed warm-up = %d, want %d", status, http.StatusServiceUnavailable)
+	}
+
+	close(proceed)
+
+	deadline := time.Now().Add(2 * time.Second)
+	for readiness() != http.StatusOK {
+		if time.Now().After(deadline) {
+			t.Fatal("readiness never flipped to 200 after warm-up succeeded")
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+}
+
+func TestRunWarmupStopsOnCancel(t *testing.T) {
+	t.Cleanup(func() { warmingUp.Store(false) })
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		runWarmup(ctx, func(ctx context.Context) error { return errors.New("down") }, time.Hour)
+	}()
+
+	cancel()
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("runWarmup kept retrying after cancel")
+	}
+
+	if !IsWarmingUp() {
+		t.Error("cancelled warm-up reported the server warm")
+	}
+}
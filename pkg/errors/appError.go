@@ -0,0 +1,100 @@
+package errors
+
+import (
+	stderrors "errors"
+	"log/slog"
+	"sort"
+)
+
+// AppError wraps an error with a message and structured fields for the logs.
+type AppError struct {
+	Msg    string
+	Err    error
+	Fields map[string]any
+}
+
+func (e *AppError) Error() string {
+	switch {
+	case e.Err == nil:
+		return e.Msg
+	case e.Msg == "":
+		return e.Err.Error()
+	default:
+		return e.Msg + ": " + e.Err.Error()
+	}
+}
+
+func (e *AppError) Unwrap() error {
+	return e.Err
+}
+
+// LogValue logs the error message along with the fields of the whole chain,
+// e.g. slog.Any("error", err).
+func (e *AppError) LogValue() slog.Value {
+	fields := Fields(e)
+
+	keys := make([]string, 0, len(fields))
+	for key := range fields {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	attrs := make([]slog.Attr, 0, len(fields)+1)
+	attrs = append(attrs, slog.String("msg", e.Error()))
+	for _, key := range keys {
+		attrs = append(attrs, slog.Any(key, fields[key]))
+	}
+
+	return slog.GroupValue(attrs...)
+}
+
+// Wrap adds msg as context to err. A nil err stays nil.
+func Wrap(err error, msg string) *AppError {
+	if err == nil {
+		return nil
+	}
+	return &AppError{Msg: msg, Err: err}
+}
+
+// WithField attaches key and value to err, on err itself when it is an
+// AppError and on a new wrapper otherwise. A nil err stays nil.
+func WithField(err error, key string, value any) *AppError {
+	if err == nil {
+		return nil
+	}
+
+	appErr, ok := err.(*AppError)
+	if !ok {
+		appErr = &AppError{Err: err}
+	}
+
+	fields := make(map[string]any, len(appErr.Fields)+1)
+	for k, v := range appErr.Fields {
+		fields[k] = v
+	}
+	fields[key] = value
+
+	return &AppError{Msg: appErr.Msg, Err: appErr.Err, Fields: fields}
+}
+
+// Fields collects the fields of every AppError in err's chain, outer ones
+// taking precedence.
+func Fields(err error) map[string]any {
+	fields := make(map[string]any)
+
+	for err != nil {
+		var appErr *AppError
+		if !stderrors.As(err, &appErr) {
+			break
+		}
+
+		for key, value := range appErr.Fields {
+			if _, ok := fields[key]; !ok {
+				fields[key] = value
+			}
+		}
+		err = appErr.Err
+	}
+
+	return fields
+}
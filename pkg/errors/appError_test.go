@@ -0,0 +1,112 @@
+package errors
+
+import (
+	"bytes"
+	"encoding/json"
+	stderrors "errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"testing"
+)
+
+func TestWrap(t *testing.T) {
+	cause := io.ErrUnexpectedEOF
+
+	tests := []struct {
+		name    string
+		err     error
+		msg     string
+		wantNil bool
+		wantMsg string
+	}{
+		{name: "adds context", err: cause, msg: "reading order", wantMsg: "reading order: unexpected EOF"},
+		{name: "no message", err: cause, wantMsg: "unexpected EOF"},
+		{name: "nil stays nil", err: nil, msg: "reading order", wantNil: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := Wrap(tt.err, tt.msg)
+			if tt.wantNil {
+				if got != nil {
+					t.Fatalf("Wrap(nil) = %v, want nil", got)
+				}
+				return
+			}
+
+			if got.Error() != tt.wantMsg {
+				t.Errorf("Error() = %q, want %q", got.Error(), tt.wantMsg)
+			}
+			if !stderrors.Is(got, cause) {
+				t.Error("wrapped error does not match its cause")
+			}
+		})
+	}
+}
+
+func TestWithField(t *testing.T) {
+	base := WithField(Wrap(io.EOF, "loading order"), "order_id", 42)
+	inner := WithField(base, "tenant", "acme")
+
+	// fields survive wrapping with fmt.Errorf, outer AppErrors take precedence
+	wrapped := fmt.Errorf("handler: %w", inner)
+	err := WithField(wrapped, "order_id", 7)
+
+	var appErr *AppError
+	if !stderrors.As(wrapped, &appErr) {
+		t.Fatal("errors.As found no AppError")
+	}
+	if appErr.Fields["tenant"] != "acme" || appErr.Fields["order_id"] != 42 {
+		t.Errorf("fields = %v, want tenant and order_id", appErr.Fields)
+	}
+	if len(base.Fields) != 1 {
+		t.Errorf("WithField modified the original error: %v", base.Fields)
+	}
+
+	tests := []struct {
+		key  string
+		want any
+	}{
+		{key: "order_id", want: 7},
+		{key: "tenant", want: "acme"},
+	}
+
+	fields := Fields(err)
+	for _, tt := range tests {
+		t.Run(tt.key, func(t *testing.T) {
+			if fields[tt.key] != tt.want {
+				t.Errorf("Fields()[%q] = %v, want %v", tt.key, fields[tt.key], tt.want)
+			}
+		})
+	}
+
+	if !stderrors.Is(err, io.EOF) {
+		t.Error("errors.Is lost the cause")
+	}
+	if WithField(nil, "k", "v") != nil {
+		t.Error("WithField(nil) is not nil")
+	}
+}
+
+func TestAppErrorLogValue(t *testing.T) {
+	var out bytes.Buffer
+	log := slog.New(slog.NewJSONHandler(&out, nil))
+
+	err := WithField(WithField(Wrap(io.EOF, "loading order"), "order_id", 42), "tenant", "acme")
+	log.Error("request failed", slog.Any("error", err))
+
+	var entry struct {
+		Error map[string]any `json:"error"`
+	}
+	if jsonErr := json.Unmarshal(out.Bytes(), &entry); jsonErr != nil {
+		t.Fatal(jsonErr)
+	}
+
+	want := map[string]any{"msg": "loading order: EOF", "order_id": float64(42), "tenant": "acme"}
+	for key, value := range want {
+		if entry.Error[key] != value {
+			t.Errorf("error.%s = %v, want %v", key, entry.Error[key], value)
+		}
+	}
+}
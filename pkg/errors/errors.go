@@ -20,4 +20,40 @@ func InternalServerError(w http.ResponseWriter, message string) {
 	}
 
 	http.Error(w, message, http.StatusBadRequest)
-}
\ No newline at end of file
+}
+
+func URITooLong(w http.ResponseWriter) {
+	http.Error(w, "URI Too Long !", http.StatusRequestURITooLong)
+}
+
+func ServiceUnavailable(w http.ResponseWriter, message string) {
+	if message == "" {
+		message = "Service Unavailable !"
+	}
+
+	http.Error(w, message, http.StatusServiceUnavailable)
+}
+
+func UnprocessableEntity(w http.ResponseWriter, message string) {
+	if message == "" {
+		message = "Unprocessable Entity !"
+	}
+
+	http.Error(w, message, http.StatusUnprocessableEntity)
+}
+
+func PayloadTooLarge(w http.ResponseWriter, message string) {
+	if message == "" {
+		message = "Payload Too Large !"
+	}
+
+	http.Error(w, message, http.StatusRequestEntityTooLarge)
+}
+
+func Forbidden(w http.ResponseWriter, message string) {
+	if message == "" {
+		message = "Forbidden !"
+	}
+
+	http.Error(w, message, http.StatusForbidden)
+}
@@ -0,0 +1,35 @@
+package helpers
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+)
+
+// ErrInvalidCursor is returned by DecodeCursor for cursors it didn't produce.
+var ErrInvalidCursor = errors.New("invalid pagination cursor")
+
+// EncodeCursor turns v, typically the sort keys of the last returned row,
+// into an opaque URL safe cursor for keyset pagination. It returns "" if v
+// can't be marshalled.
+func EncodeCursor(v interface{}) string {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return ""
+	}
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+// DecodeCursor reverses EncodeCursor into dst.
+func DecodeCursor(cursor string, dst interface{}) error {
+	data, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return ErrInvalidCursor
+	}
+
+	if err := json.Unmarshal(data, dst); err != nil {
+		return ErrInvalidCursor
+	}
+
+	return nil
+}
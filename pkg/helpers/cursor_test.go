@@ -0,0 +1,56 @@
+package helpers
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+type pageCursor struct {
+	CreatedAt time.Time `json:"created_at"`
+	ID        int64     `json:"id"`
+}
+
+func TestCursorRoundTrip(t *testing.T) {
+	want := pageCursor{CreatedAt: time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC), ID: 42}
+
+	cursor := EncodeCursor(want)
+	if cursor == "" {
+		t.Fatal("EncodeCursor() returned an empty cursor")
+	}
+
+	var got pageCursor
+	if err := DecodeCursor(cursor, &got); err != nil {
+		t.Fatalf("DecodeCursor() err = %v", err)
+	}
+	if !got.CreatedAt.Equal(want.CreatedAt) || got.ID != want.ID {
+		t.Errorf("DecodeCursor() = %+v, want %+v", got, want)
+	}
+}
+
+func TestEncodeCursorUnmarshallable(t *testing.T) {
+	if got := EncodeCursor(make(chan int)); got != "" {
+		t.Errorf("EncodeCursor(chan) = %q, want empty", got)
+	}
+}
+
+func TestDecodeCursorMalformed(t *testing.T) {
+	tests := []struct {
+		name   string
+		cursor string
+	}{
+		{name: "not base64", cursor: "%%%"},
+		{name: "padded base64", cursor: "eyJpZCI6MX0="},
+		{name: "not JSON", cursor: EncodeCursor("x")[:2]},
+		{name: "wrong shape", cursor: EncodeCursor([]int{1, 2})},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var dst pageCursor
+			if err := DecodeCursor(tt.cursor, &dst); !errors.Is(err, ErrInvalidCursor) {
+				t.Errorf("DecodeCursor(%q) err = %v, want ErrInvalidCursor", tt.cursor, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,170 @@
+package helpers
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"unicode/utf8"
+
+	apperrors "github.com/himtar/go-boilerplate/pkg/errors"
+)
+
+// MaxJSONBodyBytes caps the size of JSON request bodies read by DecodeJSON.
+const MaxJSONBodyBytes = 1 << 20
+
+// utf8BOM is the byte order mark some clients put in front of UTF-8 bodies.
+var utf8BOM = []byte{0xEF, 0xBB, 0xBF}
+
+// MaxJSONDepth caps how deeply objects and arrays may nest in bodies read by
+// DecodeJSON, guarding against payloads crafted to exhaust the decoder.
+var MaxJSONDepth = 32
+
+// DecodeJSON decodes a single JSON object from the request body into dst,
+// rejecting unknown fields, trailing data, bodies over MaxJSONBodyBytes,
+// invalid UTF-8 and nesting deeper than MaxJSONDepth. A leading UTF-8 BOM is
+// stripped.
+func DecodeJSON(w http.ResponseWriter, r *http.Request, dst interface{}) error {
+	r.Body = http.MaxBytesReader(w, r.Body, MaxJSONBodyBytes)
+
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		return fmt.Errorf("reading request body: %w", err)
+	}
+
+	body = bytes.TrimPrefix(body, utf8BOM)
+	if !utf8.Valid(body) {
+		return errors.New("request body is not valid UTF-8")
+	}
+
+	if err := checkJSONDepth(body, MaxJSONDepth); err != nil {
+		return err
+	}
+
+	decoder := json.NewDecoder(bytes.NewReader(body))
+	decoder.DisallowUnknownFields()
+
+	if err := decoder.Decode(dst); err != nil {
+		if errors.Is(err, io.EOF) {
+			return errors.New("request body is empty")
+		}
+		return fmt.Errorf("invalid JSON body: %w", err)
+	}
+
+	if decoder.More() {
+		return errors.New("request body must contain a single JSON object")
+	}
+
+	return nil
+}
+
+// checkJSONDepth fails if objects or arrays in data nest deeper than maxDepth.
+// Brackets inside strings are ignored, syntax is left to the decoder.
+func checkJSONDepth(data []byte, maxDepth int) error {
+	depth := 0
+	inString, escaped := false, false
+
+	for _, c := range data {
+		if inString {
+			switch {
+			case escaped:
+				escaped = false
+			case c == '\\':
+				escaped = true
+			case c == '"':
+				inString = false
+			}
+			continue
+		}
+
+		switch c {
+		case '"':
+			inString = true
+		case '{', '[':
+			depth++
+			if depth > maxDepth {
+				return fmt.Errorf("JSON body nests deeper than %d levels", maxDepth)
+			}
+		case '}', ']':
+			depth--
+		}
+	}
+
+	return nil
+}
+
+// BindAndValidate decodes the JSON body into dst and validates its struct tags.
+// On failure it writes a 400 (decode) or 422 (validation) response and returns
+// false, so handlers can simply return.
+func BindAndValidate(w http.ResponseWriter, r *http.Request, dst interface{}) bool {
+	if err := DecodeJSON(w, r, dst); err != nil {
+		apperrors.BadRequest(w, err.Error())
+		return false
+	}
+
+	if err := Validate(dst); err != nil {
+		apperrors.UnprocessableEntity(w, err.Error())
+		return false
+	}
+
+	return true
+}
+
+// MaxJSONArrayBodyBytes caps the size of bodies streamed by DecodeJSONArray.
+// It is higher than MaxJSONBodyBytes since elements are never held at once.
+var MaxJSONArrayBodyBytes int64 = 32 << 20
+
+// DecodeJSONArray streams the JSON array in the request body, calling fn for
+// each element in turn without loading the whole array into memory. It stops
+// at the first malformed element or error from fn, reporting its index.
+// Bodies over MaxJSONArrayBodyBytes are rejected.
+func DecodeJSONArray(r *http.Request, fn func(item json.RawMessage) error) error {
+	body := http.MaxBytesReader(nil, r.Body, MaxJSONArrayBodyBytes)
+	defer body.Close()
+
+	decoder := json.NewDecoder(skipBOM(body))
+
+	token, err := decoder.Token()
+	if err != nil {
+		if errors.Is(err, io.EOF) {
+			return errors.New("request body is empty")
+		}
+		return fmt.Errorf("invalid JSON body: %w", err)
+	}
+	if delim, ok := token.(json.Delim); !ok || delim != '[' {
+		return errors.New("request body must be a JSON array")
+	}
+
+	for index := 0; decoder.More(); index++ {
+		var item json.RawMessage
+		if err := decoder.Decode(&item); err != nil {
+			return fmt.Errorf("invalid JSON array element %d: %w", index, err)
+		}
+
+		if err := fn(item); err != nil {
+			return fmt.Errorf("array element %d: %w", index, err)
+		}
+	}
+
+	if _, err := decoder.Token(); err != nil {
+		return fmt.Errorf("invalid JSON body: %w", err)
+	}
+
+	if _, err := decoder.Token(); !errors.Is(err, io.EOF) {
+		return errors.New("request body must contain a single JSON array")
+	}
+
+	return nil
+}
+
+// skipBOM drops a leading UTF-8 byte order mark from r.
+func skipBOM(r io.Reader) io.Reader {
+	buffered := bufio.NewReader(r)
+	if prefix, err := buffered.Peek(len(utf8BOM)); err == nil && bytes.Equal(prefix, utf8BOM) {
+		buffered.Discard(len(utf8BOM))
+	}
+	return buffered
+}
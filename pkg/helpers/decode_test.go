@@ -0,0 +1,191 @@
+package helpers
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+// nested returns depth levels of arrays around an empty object.
+func nested(depth int) string {
+	return strings.Repeat("[", depth-1) + "{}" + strings.Repeat("]", depth-1)
+}
+
+func TestCheckJSONDepth(t *testing.T) {
+	tests := []struct {
+		name    string
+		body    string
+		wantErr bool
+	}{
+		{name: "flat object", body: `{"a":1}`},
+		{name: "at the limit", body: nested(MaxJSONDepth)},
+		{name: "beyond the limit", body: nested(MaxJSONDepth + 1), wantErr: true},
+		{name: "brackets inside strings", body: `{"a":"` + strings.Repeat("[", 100) + `"}`},
+		{name: "escaped quote inside string", body: `{"a":"\"` + strings.Repeat("{", 100) + `"}`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := checkJSONDepth([]byte(tt.body), MaxJSONDepth)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("checkJSONDepth() err = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestBindAndValidateDepth(t *testing.T) {
+	tests := []struct {
+		name       string
+		body       string
+		wantOK     bool
+		wantStatus int
+	}{
+		{name: "at the limit", body: `{"items":` + nested(MaxJSONDepth-1) + `}`, wantOK: true, wantStatus: http.StatusOK},
+		{name: "beyond the limit", body: `{"items":` + nested(MaxJSONDepth) + `}`, wantStatus: http.StatusBadRequest},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var dst struct {
+				Items interface{} `json:"items"`
+			}
+
+			req := httptest.NewRequest(http.MethodPost, "/items", strings.NewReader(tt.body))
+			rec := httptest.NewRecorder()
+
+			if ok := BindAndValidate(rec, req, &dst); ok != tt.wantOK {
+				t.Errorf("BindAndValidate() = %v, want %v", ok, tt.wantOK)
+			}
+			if rec.Code != tt.wantStatus {
+				t.Errorf("status = %d, want %d", rec.Code, tt.wantStatus)
+			}
+		})
+	}
+}
+
+func TestDecodeJSONEncoding(t *testing.T) {
+	tests := []struct {
+		name     string
+		body     string
+		wantErr  string
+		wantName string
+	}{
+		{name: "plain body", body: `{"name":"café"}`, wantName: "café"},
+		{name: "BOM prefixed body", body: "\xEF\xBB\xBF" + `{"name":"café"}`, wantName: "café"},
+		{name: "invalid UTF-8", body: "{\"name\":\"caf\xE9\"}", wantErr: "not valid UTF-8"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var dst struct {
+				Name string `json:"name"`
+			}
+
+			req := httptest.NewRequest(http.MethodPost, "/items", strings.NewReader(tt.body))
+			err := DecodeJSON(httptest.NewRecorder(), req, &dst)
+
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("DecodeJSON() err = %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("DecodeJSON() err = %v", err)
+			}
+			if dst.Name != tt.wantName {
+				t.Errorf("name = %q, want %q", dst.Name, tt.wantName)
+			}
+		})
+	}
+}
+
+func TestBindAndValidateInvalidUTF8(t *testing.T) {
+	var dst struct {
+		Name string `json:"name"`
+	}
+
+	req := httptest.NewRequest(http.MethodPost, "/items", strings.NewReader("{\"name\":\"\xFF\"}"))
+	rec := httptest.NewRecorder()
+
+	if BindAndValidate(rec, req, &dst) {
+		t.Error("BindAndValidate() accepted an invalid UTF-8 body")
+	}
+	if rec.Code != http.StatusBadRequest {
+		t.Errorf("status = %d, want %d", rec.Code, http.StatusBadRequest)
+	}
+}
+
+func TestDecodeJSONArray(t *testing.T) {
+	errRejected := errors.New("rejected")
+
+	tests := []struct {
+		name      string
+		body      string
+		reject    string // fn fails for this element
+		wantItems []string
+		wantErr   string
+	}{
+		{name: "every element", body: `[{"id":1}, {"id":2}, {"id":3}]`, wantItems: []string{`{"id":1}`, `{"id":2}`, `{"id":3}`}},
+		{name: "empty array", body: `[]`},
+		{name: "BOM prefixed", body: "\xEF\xBB\xBF" + `[1]`, wantItems: []string{`1`}},
+		{name: "malformed element", body: `[{"id":1}, {"id":}]`, wantItems: []string{`{"id":1}`}, wantErr: "invalid JSON array element 1"},
+		{name: "callback error", body: `[1, 2, 3]`, reject: `2`, wantItems: []string{`1`, `2`}, wantErr: "array element 1: rejected"},
+		{name: "not an array", body: `{"id":1}`, wantErr: "must be a JSON array"},
+		{name: "empty body", body: ``, wantErr: "request body is empty"},
+		{name: "trailing data", body: `[1] [2]`, wantItems: []string{`1`}, wantErr: "single JSON array"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var items []string
+			req := httptest.NewRequest(http.MethodPost, "/items", strings.NewReader(tt.body))
+
+			err := DecodeJSONArray(req, func(item json.RawMessage) error {
+				items = append(items, string(item))
+				if string(item) == tt.reject {
+					return errRejected
+				}
+				return nil
+			})
+
+			if tt.wantErr == "" && err != nil {
+				t.Fatalf("DecodeJSONArray() err = %v", err)
+			}
+			if tt.wantErr != "" && (err == nil || !strings.Contains(err.Error(), tt.wantErr)) {
+				t.Fatalf("DecodeJSONArray() err = %v, want %q", err, tt.wantErr)
+			}
+			if tt.reject != "" && !errors.Is(err, errRejected) {
+				t.Errorf("callback error not wrapped: %v", err)
+			}
+			if strings.Join(items, ",") != strings.Join(tt.wantItems, ",") {
+				t.Errorf("items = %v, want %v", items, tt.wantItems)
+			}
+		})
+	}
+}
+
+func TestDecodeJSONArrayBodyLimit(t *testing.T) {
+	previous := MaxJSONArrayBodyBytes
+	MaxJSONArrayBodyBytes = 16
+	defer func() { MaxJSONArrayBodyBytes = previous }()
+
+	var calls int
+	req := httptest.NewRequest(http.MethodPost, "/items", strings.NewReader(`[1, 2, 3, 4, 5, 6, 7, 8, 9, 10]`))
+	err := DecodeJSONArray(req, func(item json.RawMessage) error {
+		calls++
+		return nil
+	})
+
+	var maxBytesErr *http.MaxBytesError
+	if !errors.As(err, &maxBytesErr) {
+		t.Fatalf("DecodeJSONArray() err = %v, want a MaxBytesError", err)
+	}
+	if calls == 0 || calls == 10 {
+		t.Errorf("callback ran %d times, want the elements before the limit", calls)
+	}
+}
@@ -3,12 +3,12 @@ package helpers
 import (
 	"net/http"
 
-	"github.com/himtar/go-boilerplate/pkg/errors"
+	"github.com/himtar/go-boilerplate/pkg/response"
 )
 
 func ValidateMethod(method string, w http.ResponseWriter, req *http.Request) string  {
 	if req.Method != method {
-		errors.MethodNotAllowed(w)
+		response.MethodNotAllowed(w, req)
 		return "Method Not Allowed"
 	}
 
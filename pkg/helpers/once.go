@@ -0,0 +1,126 @@
+package helpers
+
+import (
+	"errors"
+	"sync"
+	"time"
+)
+
+// ErrAlreadyRun is returned by Once when the work for a key ran or is running already.
+var ErrAlreadyRun = errors.New("already run for this key")
+
+// OnceStore keeps track of the keys Once has claimed. Back it by a shared
+// store, e.g. Redis SETNX, to make Once hold across instances.
+type OnceStore interface {
+	// Claim takes key, false when it was taken already.
+	Claim(key string) (bool, error)
+
+	// Release frees key again so failed work can be retried.
+	Release(key string) error
+}
+
+// sweepOnceEvery is how often expired claims are swept from a MemoryOnceStore.
+const sweepOnceEvery = time.Minute
+
+// MemoryOnceStore is an in-memory OnceStore whose claims expire after a TTL.
+type MemoryOnceStore struct {
+	mu        sync.Mutex
+	ttl       time.Duration
+	claims    map[string]time.Time
+	lastSweep time.Time
+}
+
+// NewMemoryOnceStore creates a new instance of MemoryOnceStore.
+func NewMemoryOnceStore(ttl time.Duration) *MemoryOnceStore {
+	return &MemoryOnceStore{
+		ttl:       ttl,
+		claims:    make(map[string]time.Time),
+		lastSweep: time.Now(),
+	}
+}
+
+// Claim takes key unless an unexpired claim exists.
+func (s *MemoryOnceStore) Claim(key string) (bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := time.Now()
+	s.sweep(now)
+
+	if expiresAt, ok := s.claims[key]; ok && now.Before(expiresAt) {
+		return false, nil
+	}
+
+	s.claims[key] = now.Add(s.ttl)
+	return true, nil
+}
+
+// sweep drops expired claims, at most once per sweepOnceEvery, so keys that
+// are never claimed again don't pile up.
+func (s *MemoryOnceStore) sweep(now time.Time) {
+	if now.Sub(s.lastSweep) < sweepOnceEvery {
+		return
+	}
+	s.lastSweep = now
+
+	for key, expiresAt := range s.claims {
+		if now.After(expiresAt) {
+			delete(s.claims, key)
+		}
+	}
+}
+
+// Release drops the claim on key.
+func (s *MemoryOnceStore) Release(key string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	delete(s.claims, key)
+	return nil
+}
+
+var (
+	onceStoreMu sync.RWMutex
+	onceStore   OnceStore = NewMemoryOnceStore(24 * time.Hour)
+)
+
+// SetOnceStore replaces the store used by Once, a 24h in-memory one by default.
+func SetOnceStore(store OnceStore) {
+	onceStoreMu.Lock()
+	defer onceStoreMu.Unlock()
+
+	onceStore = store
+}
+
+// Once runs fn only if no other call claimed key before, e.g. keyed by the
+// request's idempotency key, and returns ErrAlreadyRun otherwise. When fn
+// fails or panics the key is released so the work can be retried.
+func Once(key string, fn func() error) error {
+	onceStoreMu.RLock()
+	store := onceStore
+	onceStoreMu.RUnlock()
+
+	claimed, err := store.Claim(key)
+	if err != nil {
+		return err
+	}
+	if !claimed {
+		return ErrAlreadyRun
+	}
+
+	defer func() {
+		if recovered := recover(); recovered != nil {
+			store.Release(key)
+			panic(recovered)
+		}
+	}()
+
+	if err := fn(); err != nil {
+		if releaseErr := store.Release(key); releaseErr != nil {
+			return errors.Join(err, releaseErr)
+		}
+		return err
+	}
+
+	return nil
+}
@@ -0,0 +1,69 @@
+package helpers
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestOnce(t *testing.T) {
+	errFailed := errors.New("failed")
+
+	tests := []struct {
+		name       string
+		first      func() error
+		wantFirst  error
+		wantSecond error
+	}{
+		{name: "second call is skipped", first: func() error { return nil }, wantSecond: ErrAlreadyRun},
+		{name: "failure releases the key", first: func() error { return errFailed }, wantFirst: errFailed},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			SetOnceStore(NewMemoryOnceStore(time.Hour))
+			defer SetOnceStore(NewMemoryOnceStore(24 * time.Hour))
+
+			if err := Once("key", tt.first); !errors.Is(err, tt.wantFirst) {
+				t.Fatalf("first Once() = %v, want %v", err, tt.wantFirst)
+			}
+			if err := Once("key", func() error { return nil }); !errors.Is(err, tt.wantSecond) {
+				t.Errorf("second Once() = %v, want %v", err, tt.wantSecond)
+			}
+		})
+	}
+}
+
+func TestOnceReleasesKeyOnPanic(t *testing.T) {
+	SetOnceStore(NewMemoryOnceStore(time.Hour))
+	defer SetOnceStore(NewMemoryOnceStore(24 * time.Hour))
+
+	func() {
+		defer func() {
+			if recover() == nil {
+				t.Error("panic was swallowed")
+			}
+		}()
+		Once("key", func() error { panic("boom") })
+	}()
+
+	if err := Once("key", func() error { return nil }); err != nil {
+		t.Errorf("Once() after panic = %v, want nil", err)
+	}
+}
+
+func TestMemoryOnceStoreSweepsExpiredClaims(t *testing.T) {
+	store := NewMemoryOnceStore(time.Millisecond)
+	store.Claim("old")
+
+	time.Sleep(2 * time.Millisecond)
+	store.lastSweep = time.Now().Add(-sweepOnceEvery)
+	store.Claim("new")
+
+	if _, ok := store.claims["old"]; ok {
+		t.Error("expired claim survived the sweep")
+	}
+	if _, ok := store.claims["new"]; !ok {
+		t.Error("fresh claim was swept")
+	}
+}
@@ -0,0 +1,118 @@
+package helpers
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"mime/multipart"
+	"net/http"
+
+	"github.com/himtar/go-boilerplate/pkg/response"
+)
+
+// ErrFileTooLarge is returned while reading an upload past its size cap,
+// SendUploadError answers it with a 413.
+var ErrFileTooLarge = errors.New("uploaded file is too large")
+
+// ReadMultipartFile streams the file uploaded under field without buffering it
+// in memory. Reading more than maxBytes from the returned reader fails with
+// ErrFileTooLarge. The returned header has no Size as the file isn't read yet.
+func ReadMultipartFile(r *http.Request, field string, maxBytes int64) (io.Reader, *multipart.FileHeader, error) {
+	mr, err := r.MultipartReader()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	for {
+		part, err := mr.NextPart()
+		if err == io.EOF {
+			return nil, nil, fmt.Errorf("file field %q not found", field)
+		}
+		if err != nil {
+			return nil, nil, err
+		}
+
+		if part.FormName() != field || part.FileName() == "" {
+			part.Close()
+			continue
+		}
+
+		header := &multipart.FileHeader{
+			Filename: part.FileName(),
+			Header:   part.Header,
+		}
+
+		return &cappedReader{r: part, remaining: maxBytes}, header, nil
+	}
+}
+
+// SendUploadError answers an error from ReadMultipartFile or from reading the
+// returned file: 413 when the file or body is over its cap, 400 otherwise.
+func SendUploadError(w http.ResponseWriter, err error) {
+	var bodyTooLarge *http.MaxBytesError
+	if errors.Is(err, ErrFileTooLarge) || errors.As(err, &bodyTooLarge) {
+		response.Send(w, http.StatusRequestEntityTooLarge, "Uploaded file is too large", nil)
+		return
+	}
+
+	response.SendBadRequest(w, err.Error())
+}
+
+// cappedReader fails with ErrFileTooLarge once more than the allowed bytes have been read.
+type cappedReader struct {
+	r         io.Reader
+	remaining int64
+}
+
+func (c *cappedReader) Read(p []byte) (int, error) {
+	if c.remaining < 0 {
+		return 0, ErrFileTooLarge
+	}
+
+	// read one byte past the cap to tell "exactly full" from "too large"
+	if int64(len(p)) > c.remaining+1 {
+		p = p[:c.remaining+1]
+	}
+
+	n, err := c.r.Read(p)
+	c.remaining -= int64(n)
+	if c.remaining < 0 {
+		return n + int(c.remaining), ErrFileTooLarge
+	}
+
+	return n, err
+}
+
+// LogProgress wraps r so a progress entry is logged every `every` bytes read.
+func LogProgress(r io.Reader, every int64, logger *slog.Logger, name string) io.Reader {
+	if logger == nil {
+		logger = slog.Default()
+	}
+	return &progressReader{r: r, every: every, logger: logger, name: name, next: every}
+}
+
+type progressReader struct {
+	r      io.Reader
+	every  int64
+	logger *slog.Logger
+	name   string
+	read   int64
+	next   int64
+}
+
+func (p *progressReader) Read(b []byte) (int, error) {
+	n, err := p.r.Read(b)
+	p.read += int64(n)
+
+	if p.every > 0 && p.read >= p.next {
+		p.logger.Info("upload progress", slog.String("file", p.name), slog.Int64("bytes_read", p.read))
+		p.next = (p.read/p.every + 1) * p.every
+	}
+
+	if err == io.EOF {
+		p.logger.Info("upload complete", slog.String("file", p.name), slog.Int64("bytes_read", p.read))
+	}
+
+	return n, err
+}
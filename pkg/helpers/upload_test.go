@@ -0,0 +1,108 @@
+package helpers
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"log/slog"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"testing/iotest"
+)
+
+func newUploadRequest(t *testing.T, field, content string) *http.Request {
+	t.Helper()
+
+	var body bytes.Buffer
+	mw := multipart.NewWriter(&body)
+	part, err := mw.CreateFormFile(field, "report.csv")
+	if err != nil {
+		t.Fatal(err)
+	}
+	part.Write([]byte(content))
+	mw.Close()
+
+	req := httptest.NewRequest(http.MethodPost, "/upload", &body)
+	req.Header.Set("Content-Type", mw.FormDataContentType())
+	return req
+}
+
+func TestReadMultipartFile(t *testing.T) {
+	tests := []struct {
+		name       string
+		field      string
+		content    string
+		maxBytes   int64
+		wantStatus int
+	}{
+		{name: "within cap", field: "file", content: "a,b,c", maxBytes: 5, wantStatus: http.StatusOK},
+		{name: "over cap", field: "file", content: strings.Repeat("x", 10), maxBytes: 5, wantStatus: http.StatusRequestEntityTooLarge},
+		{name: "missing field", field: "other", content: "a,b,c", maxBytes: 5, wantStatus: http.StatusBadRequest},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got string
+			handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				file, header, err := ReadMultipartFile(r, "file", tt.maxBytes)
+				if err != nil {
+					SendUploadError(w, err)
+					return
+				}
+
+				data, err := io.ReadAll(file)
+				if err != nil {
+					SendUploadError(w, err)
+					return
+				}
+
+				if header.Filename != "report.csv" {
+					t.Errorf("filename = %q, want report.csv", header.Filename)
+				}
+				got = string(data)
+			})
+
+			rec := httptest.NewRecorder()
+			handler.ServeHTTP(rec, newUploadRequest(t, tt.field, tt.content))
+
+			if rec.Code != tt.wantStatus {
+				t.Fatalf("status = %d, want %d", rec.Code, tt.wantStatus)
+			}
+			if tt.wantStatus == http.StatusOK && got != tt.content {
+				t.Errorf("content = %q, want %q", got, tt.content)
+			}
+		})
+	}
+}
+
+func TestCappedReader(t *testing.T) {
+	r := &cappedReader{r: strings.NewReader("123456"), remaining: 5}
+	data, err := io.ReadAll(r)
+	if !errors.Is(err, ErrFileTooLarge) {
+		t.Fatalf("err = %v, want %v", err, ErrFileTooLarge)
+	}
+	if string(data) != "12345" {
+		t.Errorf("data = %q, want %q", data, "12345")
+	}
+}
+
+func TestLogProgress(t *testing.T) {
+	var out bytes.Buffer
+	logger := slog.New(slog.NewTextHandler(&out, nil))
+
+	r := LogProgress(strings.NewReader(strings.Repeat("x", 250)), 100, logger, "report.csv")
+	if _, err := io.Copy(io.Discard, iotest.OneByteReader(r)); err != nil {
+		t.Fatal(err)
+	}
+
+	log := out.String()
+	if got := strings.Count(log, "upload progress"); got != 2 {
+		t.Errorf("progress entries = %d, want 2: %s", got, log)
+	}
+	if !strings.Contains(log, "upload complete") || !strings.Contains(log, "bytes_read=250") {
+		t.Errorf("missing completion entry: %s", log)
+	}
+}
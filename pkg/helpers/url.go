@@ -0,0 +1,43 @@
+package helpers
+
+import (
+	"net/http"
+	"strings"
+)
+
+// AbsoluteURL builds an absolute URL for path on the host the client used,
+// e.g. for pagination links or verification emails. The X-Forwarded-Proto and
+// X-Forwarded-Host headers are only honoured when behindProxy is set, since
+// without a proxy overwriting them any client can inject a host into links.
+func AbsoluteURL(r *http.Request, path string, behindProxy bool) string {
+	scheme := "http"
+	if r.TLS != nil {
+		scheme = "https"
+	}
+
+	host := r.Host
+
+	if behindProxy {
+		if proto := LastForwarded(r.Header.Get("X-Forwarded-Proto")); proto != "" {
+			scheme = strings.ToLower(proto)
+		}
+		if forwardedHost := LastForwarded(r.Header.Get("X-Forwarded-Host")); forwardedHost != "" {
+			host = forwardedHost
+		}
+	}
+
+	if !strings.HasPrefix(path, "/") {
+		path = "/" + path
+	}
+
+	return scheme + "://" + host + path
+}
+
+// LastForwarded returns the last entry of a forwarded header, the one appended
+// by the proxy closest to us. Earlier entries may come from the client.
+func LastForwarded(value string) string {
+	if i := strings.LastIndex(value, ","); i >= 0 {
+		value = value[i+1:]
+	}
+	return strings.TrimSpace(value)
+}
@@ -0,0 +1,73 @@
+package helpers
+
+import (
+	"crypto/tls"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestAbsoluteURL(t *testing.T) {
+	tests := []struct {
+		name        string
+		headers     map[string]string
+		tls         bool
+		behindProxy bool
+		path        string
+		want        string
+	}{
+		{name: "plain request", path: "/users", want: "http://example.com/users"},
+		{name: "adds leading slash", path: "users", want: "http://example.com/users"},
+		{name: "tls connection", tls: true, path: "/users", want: "https://example.com/users"},
+		{
+			name:    "ignores forwarded headers without proxy",
+			headers: map[string]string{"X-Forwarded-Proto": "https", "X-Forwarded-Host": "evil.test"},
+			path:    "/users",
+			want:    "http://example.com/users",
+		},
+		{
+			name:        "uses forwarded headers behind proxy",
+			headers:     map[string]string{"X-Forwarded-Proto": "HTTPS", "X-Forwarded-Host": "api.example.com"},
+			behindProxy: true,
+			path:        "/users",
+			want:        "https://api.example.com/users",
+		},
+		{
+			name:        "takes the entry appended by the proxy",
+			headers:     map[string]string{"X-Forwarded-Proto": "http, https", "X-Forwarded-Host": "evil.test, api.example.com"},
+			behindProxy: true,
+			path:        "/users",
+			want:        "https://api.example.com/users",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest("GET", "http://example.com/", nil)
+			for name, value := range tt.headers {
+				req.Header.Set(name, value)
+			}
+			if tt.tls {
+				req.TLS = &tls.ConnectionState{}
+			}
+
+			if got := AbsoluteURL(req, tt.path, tt.behindProxy); got != tt.want {
+				t.Errorf("AbsoluteURL() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestLastForwarded(t *testing.T) {
+	tests := map[string]string{
+		"":            "",
+		"https":       "https",
+		"http, https": "https",
+		"a,b , c ":    "c",
+	}
+
+	for value, want := range tests {
+		if got := LastForwarded(value); got != want {
+			t.Errorf("LastForwarded(%q) = %q, want %q", value, got, want)
+		}
+	}
+}
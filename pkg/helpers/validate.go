@@ -0,0 +1,103 @@
+package helpers
+
+import (
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// ValidationErrors lists every field failing its `validate` tag.
+type ValidationErrors []string
+
+func (v ValidationErrors) Error() string {
+	return "validation failed: " + strings.Join(v, "; ")
+}
+
+// Validate checks the `validate` struct tags of v, a struct or pointer to one.
+// Supported rules are required, min=N and max=N, where N bounds the length of
+// strings, slices and maps or the value of numbers.
+func Validate(v interface{}) error {
+	val := reflect.Indirect(reflect.ValueOf(v))
+	if val.Kind() != reflect.Struct {
+		return nil
+	}
+
+	var errs ValidationErrors
+	typ := val.Type()
+
+	for i := 0; i < typ.NumField(); i++ {
+		field := typ.Field(i)
+		tag := field.Tag.Get("validate")
+		if tag == "" || !field.IsExported() {
+			continue
+		}
+
+		name := jsonFieldName(field)
+		for _, rule := range strings.Split(tag, ",") {
+			if msg := checkRule(val.Field(i), rule); msg != "" {
+				errs = append(errs, name+" "+msg)
+			}
+		}
+	}
+
+	if len(errs) > 0 {
+		return errs
+	}
+
+	return nil
+}
+
+// jsonFieldName reports the field the way clients see it in the payload.
+func jsonFieldName(field reflect.StructField) string {
+	name := strings.Split(field.Tag.Get("json"), ",")[0]
+	if name == "" || name == "-" {
+		return field.Name
+	}
+	return name
+}
+
+func checkRule(value reflect.Value, rule string) string {
+	name, arg, _ := strings.Cut(strings.TrimSpace(rule), "=")
+
+	switch name {
+	case "required":
+		if value.IsZero() {
+			return "is required"
+		}
+	case "min", "max":
+		limit, err := strconv.ParseFloat(arg, 64)
+		if err != nil {
+			return fmt.Sprintf("has an invalid %s rule", name)
+		}
+
+		size, ok := measure(value)
+		if !ok {
+			return ""
+		}
+
+		if name == "min" && size < limit {
+			return fmt.Sprintf("must be at least %s", arg)
+		}
+		if name == "max" && size > limit {
+			return fmt.Sprintf("must be at most %s", arg)
+		}
+	}
+
+	return ""
+}
+
+// measure returns the length of sized kinds or the value of numeric kinds.
+func measure(value reflect.Value) (float64, bool) {
+	switch value.Kind() {
+	case reflect.String, reflect.Slice, reflect.Map, reflect.Array:
+		return float64(value.Len()), true
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return float64(value.Int()), true
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return float64(value.Uint()), true
+	case reflect.Float32, reflect.Float64:
+		return value.Float(), true
+	}
+	return 0, false
+}
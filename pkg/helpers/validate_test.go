@@ -0,0 +1,61 @@
+package helpers
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+type signupRequest struct {
+	Email string   `json:"email" validate:"required"`
+	Name  string   `json:"name" validate:"min=2,max=10"`
+	Age   int      `json:"age" validate:"min=18"`
+	Tags  []string `json:"tags" validate:"max=2"`
+}
+
+func TestBindAndValidate(t *testing.T) {
+	tests := []struct {
+		name       string
+		body       string
+		wantOK     bool
+		wantStatus int
+		wantBody   string
+	}{
+		{name: "valid", body: `{"email":"a@b.c","name":"Ada","age":36}`, wantOK: true, wantStatus: http.StatusOK},
+		{name: "malformed JSON", body: `{"email":`, wantStatus: http.StatusBadRequest},
+		{name: "unknown field", body: `{"email":"a@b.c","name":"Ada","age":36,"admin":true}`, wantStatus: http.StatusBadRequest},
+		{name: "missing required field", body: `{"name":"Ada","age":36}`, wantStatus: http.StatusUnprocessableEntity, wantBody: "email is required"},
+		{name: "below min", body: `{"email":"a@b.c","name":"A","age":12}`, wantStatus: http.StatusUnprocessableEntity, wantBody: "age must be at least 18"},
+		{name: "above max", body: `{"email":"a@b.c","name":"Ada","age":36,"tags":["a","b","c"]}`, wantStatus: http.StatusUnprocessableEntity, wantBody: "tags must be at most 2"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := httptest.NewRecorder()
+			req := httptest.NewRequest(http.MethodPost, "/signup", strings.NewReader(tt.body))
+
+			var dst signupRequest
+			ok := BindAndValidate(rec, req, &dst)
+
+			if ok != tt.wantOK {
+				t.Fatalf("BindAndValidate() = %v, want %v", ok, tt.wantOK)
+			}
+			if rec.Code != tt.wantStatus {
+				t.Errorf("status = %d, want %d", rec.Code, tt.wantStatus)
+			}
+			if !strings.Contains(rec.Body.String(), tt.wantBody) {
+				t.Errorf("body = %q, want it to contain %q", rec.Body.String(), tt.wantBody)
+			}
+			if ok && dst.Email != "a@b.c" {
+				t.Errorf("decoded email = %q", dst.Email)
+			}
+		})
+	}
+}
+
+func TestValidateIgnoresNonStructs(t *testing.T) {
+	if err := Validate("plain"); err != nil {
+		t.Errorf("Validate(string) = %v, want nil", err)
+	}
+}
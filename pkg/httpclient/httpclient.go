@@ -0,0 +1,83 @@
+package httpclient
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/himtar/go-boilerplate/pkg/trace"
+)
+
+// DefaultClient is used by clients created through WithRequest.
+var DefaultClient = &http.Client{Timeout: 30 * time.Second}
+
+// Client sends outbound requests bound to an incoming request's context.
+type Client struct {
+	ctx    context.Context
+	client *http.Client
+}
+
+// WithRequest returns a client whose calls are cancelled together with ctx and
+// carry its trace ID as X-Trace-ID and a W3C traceparent header. Pass the
+// incoming r.Context(); it is cancelled once the handler returns, so don't use
+// it for work that outlives the request.
+func WithRequest(ctx context.Context) *Client {
+	return &Client{ctx: ctx, client: DefaultClient}
+}
+
+// Do sends a copy of req carrying the bound context and trace headers, req
+// itself is left untouched so it can be retried or sent concurrently.
+func (c *Client) Do(req *http.Request) (*http.Response, error) {
+	req = req.Clone(c.ctx)
+
+	if traceID := trace.ID(c.ctx); traceID != "" {
+		req.Header.Set(trace.Header, traceID)
+
+		if isTraceParentID(traceID) && req.Header.Get("traceparent") == "" {
+			req.Header.Set("traceparent", "00-"+traceID+"-"+newSpanID()+"-01")
+		}
+	}
+
+	return c.client.Do(req)
+}
+
+// Get issues a GET to url.
+func (c *Client) Get(url string) (*http.Response, error) {
+	req, err := http.NewRequest(http.MethodGet, url, nil)
+	if err != nil {
+		return nil, err
+	}
+	return c.Do(req)
+}
+
+// Post issues a POST to url with the given content type and body.
+func (c *Client) Post(url, contentType string, body io.Reader) (*http.Response, error) {
+	req, err := http.NewRequest(http.MethodPost, url, body)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", contentType)
+	return c.Do(req)
+}
+
+// isTraceParentID reports whether id can be used as a W3C trace-id (32 lowercase hex chars).
+func isTraceParentID(id string) bool {
+	if len(id) != 32 {
+		return false
+	}
+	for _, c := range id {
+		if (c < '0' || c > '9') && (c < 'a' || c > 'f') {
+			return false
+		}
+	}
+	return id != "00000000000000000000000000000000"
+}
+
+func newSpanID() string {
+	b := make([]byte, 8)
+	rand.Read(b)
+	return hex.EncodeToString(b)
+}
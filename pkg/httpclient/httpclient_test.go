@@ -0,0 +1,118 @@
+package httpclient
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/himtar/go-boilerplate/pkg/trace"
+)
+
+func TestWithRequestPropagatesTrace(t *testing.T) {
+	const hexTraceID = "4bf92f3577b34da6a3ce929d0e0e4736"
+
+	tests := []struct {
+		name            string
+		traceID         string
+		wantTraceParent bool
+	}{
+		{name: "W3C compatible trace ID", traceID: hexTraceID, wantTraceParent: true},
+		{name: "custom trace ID", traceID: "req-42"},
+		{name: "no trace ID"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got http.Header
+			upstream := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				got = r.Header.Clone()
+			}))
+			defer upstream.Close()
+
+			ctx := context.Background()
+			if tt.traceID != "" {
+				ctx = trace.NewContext(ctx, tt.traceID)
+			}
+
+			res, err := WithRequest(ctx).Get(upstream.URL)
+			if err != nil {
+				t.Fatal(err)
+			}
+			res.Body.Close()
+
+			if got.Get(trace.Header) != tt.traceID {
+				t.Errorf("X-Trace-ID = %q, want %q", got.Get(trace.Header), tt.traceID)
+			}
+
+			traceParent := got.Get("traceparent")
+			if tt.wantTraceParent != (traceParent != "") {
+				t.Fatalf("traceparent = %q, want present = %v", traceParent, tt.wantTraceParent)
+			}
+			if tt.wantTraceParent && !strings.HasPrefix(traceParent, "00-"+hexTraceID+"-") {
+				t.Errorf("traceparent = %q, want it to carry the trace ID", traceParent)
+			}
+		})
+	}
+}
+
+func TestWithRequestCancelledWithInboundContext(t *testing.T) {
+	release := make(chan struct{})
+	upstream := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-release:
+		case <-r.Context().Done():
+		}
+	}))
+	defer upstream.Close()
+	defer close(release)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	time.AfterFunc(20*time.Millisecond, cancel)
+
+	_, err := WithRequest(ctx).Get(upstream.URL)
+	if !errors.Is(err, context.Canceled) {
+		t.Errorf("Get() error = %v, want %v", err, context.Canceled)
+	}
+}
+
+func TestDoLeavesCallerRequestUnchanged(t *testing.T) {
+	const hexTraceID = "4bf92f3577b34da6a3ce929d0e0e4736"
+
+	var traceParents []string
+	upstream := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		traceParents = append(traceParents, r.Header.Get("traceparent"))
+	}))
+	defer upstream.Close()
+
+	req, err := http.NewRequest(http.MethodGet, upstream.URL, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	req.Header.Set("Accept", "application/json")
+	before := req.Header.Clone()
+
+	client := WithRequest(trace.NewContext(context.Background(), hexTraceID))
+	for i := 0; i < 2; i++ {
+		res, err := client.Do(req)
+		if err != nil {
+			t.Fatal(err)
+		}
+		res.Body.Close()
+	}
+
+	if !reflect.DeepEqual(req.Header, before) {
+		t.Errorf("caller header = %v, want it unchanged %v", req.Header, before)
+	}
+	if req.Context() != context.Background() {
+		t.Error("caller request context was replaced")
+	}
+	// every call gets its own span
+	if len(traceParents) != 2 || traceParents[0] == traceParents[1] {
+		t.Errorf("traceparents = %v, want a fresh span per call", traceParents)
+	}
+}
@@ -0,0 +1,34 @@
+package logger
+
+import (
+	"context"
+	"log/slog"
+	"sort"
+)
+
+// AuditStream tags audit entries so pipelines can tell them from request logs.
+const AuditStream = "audit"
+
+// Audit records a security sensitive action, e.g. a login or a permission
+// change. Entries carry "stream": "audit" and are written to Config.AuditWriter
+// when set, to the main output otherwise. They are never filtered by level.
+// The request's AuditContext, if ctx carries one, is added to the entry.
+func (l *Logger) Audit(ctx context.Context, action string, fields map[string]interface{}) {
+	keys := make([]string, 0, len(fields))
+	for key := range fields {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	attrs := make([]slog.Attr, 0, len(keys)+1)
+	attrs = append(attrs, slog.String("action", action))
+	for _, key := range keys {
+		attrs = append(attrs, slog.Any(key, fields[key]))
+	}
+
+	if ac, ok := AuditContextFrom(ctx); ok {
+		attrs = append(attrs, ac.attrs()...)
+	}
+
+	l.audit.LogAttrs(ctx, slog.LevelInfo, "audit", attrs...)
+}
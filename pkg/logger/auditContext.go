@@ -0,0 +1,48 @@
+package logger
+
+import (
+	"context"
+	"log/slog"
+)
+
+type auditContextKey struct{}
+
+// AuditContext is the security relevant metadata of the request behind an
+// audit entry.
+type AuditContext struct {
+	ClientIP  string
+	UserAgent string
+	RequestID string
+	TraceID   string
+}
+
+// attrs returns the non-empty fields as log attributes.
+func (a AuditContext) attrs() []slog.Attr {
+	var attrs []slog.Attr
+	for _, field := range []struct{ key, value string }{
+		{"client_ip", a.ClientIP},
+		{"user_agent", a.UserAgent},
+		{"request_id", a.RequestID},
+		{"trace_id", a.TraceID},
+	} {
+		if field.value != "" {
+			attrs = append(attrs, slog.String(field.key, field.value))
+		}
+	}
+	return attrs
+}
+
+// WithAuditContext returns a copy of ctx carrying ac, added to every audit
+// entry logged with it.
+func WithAuditContext(ctx context.Context, ac AuditContext) context.Context {
+	return context.WithValue(ctx, auditContextKey{}, ac)
+}
+
+// AuditContextFrom returns the AuditContext stored by WithAuditContext.
+func AuditContextFrom(ctx context.Context) (AuditContext, bool) {
+	if ctx == nil {
+		return AuditContext{}, false
+	}
+	ac, ok := ctx.Value(auditContextKey{}).(AuditContext)
+	return ac, ok
+}
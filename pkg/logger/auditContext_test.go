@@ -0,0 +1,44 @@
+package logger
+
+import (
+	"context"
+	"testing"
+)
+
+func TestAuditContextAttrs(t *testing.T) {
+	tests := []struct {
+		name     string
+		ac       AuditContext
+		wantKeys []string
+	}{
+		{name: "all fields", ac: AuditContext{ClientIP: "203.0.113.7", UserAgent: "curl", RequestID: "req-1", TraceID: "trace-1"}, wantKeys: []string{"client_ip", "user_agent", "request_id", "trace_id"}},
+		{name: "empty fields are left out", ac: AuditContext{RequestID: "req-1"}, wantKeys: []string{"request_id"}},
+		{name: "empty", ac: AuditContext{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			attrs := tt.ac.attrs()
+			if len(attrs) != len(tt.wantKeys) {
+				t.Fatalf("got %d attrs, want %d", len(attrs), len(tt.wantKeys))
+			}
+			for i, attr := range attrs {
+				if attr.Key != tt.wantKeys[i] {
+					t.Errorf("attr %d = %s, want %s", i, attr.Key, tt.wantKeys[i])
+				}
+			}
+		})
+	}
+}
+
+func TestAuditContextFrom(t *testing.T) {
+	if _, ok := AuditContextFrom(context.Background()); ok {
+		t.Error("AuditContextFrom() found a context that was never set")
+	}
+
+	want := AuditContext{ClientIP: "203.0.113.7"}
+	got, ok := AuditContextFrom(WithAuditContext(context.Background(), want))
+	if !ok || got != want {
+		t.Errorf("AuditContextFrom() = %+v, %v, want %+v, true", got, ok, want)
+	}
+}
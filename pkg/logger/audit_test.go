@@ -0,0 +1,79 @@
+package logger
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestAudit(t *testing.T) {
+	mainPath := filepath.Join(t.TempDir(), "app.log")
+	var audit bytes.Buffer
+
+	l, err := New(Config{ServiceName: "orders", Format: "json", FilePath: mainPath, AuditWriter: &audit})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ctx := WithAuditContext(context.Background(), AuditContext{ClientIP: "203.0.113.7", RequestID: "req-1"})
+	l.Audit(ctx, "login", map[string]interface{}{"user_id": "42", "method": "password"})
+	l.Info("request completed")
+
+	if err := l.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	var entry map[string]interface{}
+	if err := json.Unmarshal(audit.Bytes(), &entry); err != nil {
+		t.Fatalf("decoding audit entry %q: %v", audit.String(), err)
+	}
+
+	want := map[string]interface{}{
+		"stream":     AuditStream,
+		"action":     "login",
+		"user_id":    "42",
+		"method":     "password",
+		"client_ip":  "203.0.113.7",
+		"request_id": "req-1",
+		"service":    "orders",
+	}
+	for key, value := range want {
+		if entry[key] != value {
+			t.Errorf("%s = %v, want %v", key, entry[key], value)
+		}
+	}
+
+	if strings.Contains(audit.String(), "request completed") {
+		t.Error("request log reached the audit writer")
+	}
+
+	main, err := os.ReadFile(mainPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(string(main), `"stream":"audit"`) {
+		t.Error("audit entry reached the main output")
+	}
+	if !strings.Contains(string(main), "request completed") {
+		t.Error("request log missing from the main output")
+	}
+}
+
+func TestAuditIgnoresLevel(t *testing.T) {
+	var audit bytes.Buffer
+	l, err := New(Config{ServiceName: "orders", Format: "json", Console: true, AuditWriter: &audit})
+	if err != nil {
+		t.Fatal(err)
+	}
+	l.SetLevel(LevelFatal)
+
+	l.Audit(context.Background(), "permission_change", nil)
+
+	if !strings.Contains(audit.String(), `"action":"permission_change"`) {
+		t.Errorf("audit entry filtered by level: %q", audit.String())
+	}
+}
@@ -0,0 +1,157 @@
+package logger
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// ErrBatchWriterClosed is returned by BatchWriter.Write after Close.
+var ErrBatchWriterClosed = errors.New("batch writer closed")
+
+// BatchWriter buffers newline-delimited log entries and hands them to the
+// underlying writer in a single write once maxBytes is reached, every
+// interval, and on Close. It cuts syscalls for high volume JSON logging.
+type BatchWriter struct {
+	mu       sync.Mutex
+	w        io.Writer
+	buf      bytes.Buffer
+	maxBytes int
+	closed   bool
+
+	// pending counts buffered entries, readable while a flush holds mu
+	pending atomic.Int64
+
+	done chan struct{}
+	wg   sync.WaitGroup
+}
+
+// NewBatchWriter creates a new instance of BatchWriter writing to w. maxBytes
+// must be positive, anything else would flush on every write.
+func NewBatchWriter(w io.Writer, maxBytes int, interval time.Duration) (*BatchWriter, error) {
+	if maxBytes <= 0 {
+		return nil, fmt.Errorf("batch size must be positive, got %d", maxBytes)
+	}
+
+	b := &BatchWriter{
+		w:        w,
+		maxBytes: maxBytes,
+		done:     make(chan struct{}),
+	}
+
+	if interval > 0 {
+		b.wg.Add(1)
+		go b.flushEvery(interval)
+	}
+
+	return b, nil
+}
+
+// Write queues p, which is expected to hold whole entries, flushing when the
+// batch is full. Entries written after Close are refused with ErrBatchWriterClosed.
+func (b *BatchWriter) Write(p []byte) (int, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.closed {
+		return 0, ErrBatchWriterClosed
+	}
+
+	b.buf.Write(p)
+	b.pending.Add(int64(bytes.Count(p, []byte{'\n'})))
+
+	if b.buf.Len() >= b.maxBytes {
+		if err := b.flushLocked(); err != nil {
+			return len(p), err
+		}
+	}
+
+	return len(p), nil
+}
+
+// Flush writes every queued entry to the underlying writer.
+func (b *BatchWriter) Flush() error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	return b.flushLocked()
+}
+
+// Close stops the interval flush and writes what is left.
+func (b *BatchWriter) Close() error {
+	select {
+	case <-b.done:
+	default:
+		close(b.done)
+	}
+	b.wg.Wait()
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.closed = true
+	return b.flushLocked()
+}
+
+func (b *BatchWriter) flushLocked() error {
+	if b.buf.Len() == 0 {
+		return nil
+	}
+
+	_, err := b.w.Write(b.buf.Bytes())
+	b.buf.Reset()
+	b.pending.Store(0)
+	return err
+}
+
+// UnflushedError reports entries still buffered when CloseContext gave up.
+type UnflushedError struct {
+	Entries int64
+	Err     error
+}
+
+func (e *UnflushedError) Error() string {
+	return fmt.Sprintf("closing log writer: %d entries unflushed: %v", e.Entries, e.Err)
+}
+
+func (e *UnflushedError) Unwrap() error {
+	return e.Err
+}
+
+// CloseContext is Close bounded by ctx, so a blocked underlying writer can't
+// hang shutdown. When ctx ends first it returns an *UnflushedError with the
+// number of entries left behind; the flush keeps going in the background.
+func (b *BatchWriter) CloseContext(ctx context.Context) error {
+	done := make(chan error, 1)
+	go func() {
+		done <- b.Close()
+	}()
+
+	select {
+	case err := <-done:
+		return err
+	case <-ctx.Done():
+		return &UnflushedError{Entries: b.pending.Load(), Err: ctx.Err()}
+	}
+}
+
+func (b *BatchWriter) flushEvery(interval time.Duration) {
+	defer b.wg.Done()
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			b.Flush()
+		case <-b.done:
+			return
+		}
+	}
+}
@@ -0,0 +1,269 @@
+package logger
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// countingWriter records what it got and in how many writes.
+type countingWriter struct {
+	mu     sync.Mutex
+	buf    bytes.Buffer
+	writes int
+}
+
+func (c *countingWriter) Write(p []byte) (int, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.writes++
+	return c.buf.Write(p)
+}
+
+func (c *countingWriter) snapshot() (string, int) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.buf.String(), c.writes
+}
+
+func TestBatchWriterFlushes(t *testing.T) {
+	entry := `{"msg":"metric"}` + "\n"
+
+	tests := []struct {
+		name       string
+		maxBytes   int
+		interval   time.Duration
+		entries    int
+		flush      func(b *BatchWriter)
+		wantWrites int
+	}{
+		{name: "on size threshold", maxBytes: 3 * len(entry), entries: 6, flush: func(*BatchWriter) {}, wantWrites: 2},
+		{
+			name:     "on interval",
+			maxBytes: 1 << 20,
+			interval: 10 * time.Millisecond,
+			entries:  3,
+			flush: func(*BatchWriter) {
+				time.Sleep(50 * time.Millisecond)
+			},
+			wantWrites: 1,
+		},
+		{name: "on close", maxBytes: 1 << 20, entries: 3, flush: func(b *BatchWriter) { b.Close() }, wantWrites: 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			out := &countingWriter{}
+			b, err := NewBatchWriter(out, tt.maxBytes, tt.interval)
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer b.Close()
+
+			for i := 0; i < tt.entries; i++ {
+				b.Write([]byte(entry))
+			}
+			tt.flush(b)
+
+			got, writes := out.snapshot()
+			if want := strings.Repeat(entry, tt.entries); got != want {
+				t.Errorf("written = %q, want %q", got, want)
+			}
+			if writes != tt.wantWrites {
+				t.Errorf("writes = %d, want %d", writes, tt.wantWrites)
+			}
+		})
+	}
+}
+
+func TestBatchWriterRejectsInvalidSize(t *testing.T) {
+	for _, maxBytes := range []int{0, -1} {
+		if _, err := NewBatchWriter(io.Discard, maxBytes, 0); err == nil {
+			t.Errorf("NewBatchWriter(maxBytes=%d) succeeded", maxBytes)
+		}
+	}
+}
+
+func TestBatchWriterWriteAfterClose(t *testing.T) {
+	b, err := NewBatchWriter(io.Discard, 1<<10, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	b.Close()
+
+	if _, err := b.Write([]byte("late\n")); !errors.Is(err, ErrBatchWriterClosed) {
+		t.Errorf("Write after Close = %v, want %v", err, ErrBatchWriterClosed)
+	}
+}
+
+// blockingWriter blocks every write until release is closed.
+type blockingWriter struct {
+	release chan struct{}
+	countingWriter
+}
+
+func (b *blockingWriter) Write(p []byte) (int, error) {
+	<-b.release
+	return b.countingWriter.Write(p)
+}
+
+func TestBatchWriterCloseContext(t *testing.T) {
+	entry := []byte(`{"msg":"metric"}` + "\n")
+
+	t.Run("blocked writer", func(t *testing.T) {
+		w := &blockingWriter{release: make(chan struct{})}
+		b, err := NewBatchWriter(w, 1<<20, 0)
+		if err != nil {
+			t.Fatal(err)
+		}
+		for i := 0; i < 3; i++ {
+			b.Write(entry)
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+		defer cancel()
+
+		start := time.Now()
+		err = b.CloseContext(ctx)
+		if elapsed := time.Since(start); elapsed > time.Second {
+			t.Errorf("CloseContext took %v past its deadline", elapsed)
+		}
+
+		var unflushed *UnflushedError
+		if !errors.As(err, &unflushed) {
+			t.Fatalf("CloseContext() = %v, want an *UnflushedError", err)
+		}
+		if unflushed.Entries != 3 {
+			t.Errorf("unflushed entries = %d, want 3", unflushed.Entries)
+		}
+		if !errors.Is(err, context.DeadlineExceeded) {
+			t.Errorf("CloseContext() = %v, want it to wrap context.DeadlineExceeded", err)
+		}
+
+		// the flush carries on once the writer unblocks
+		close(w.release)
+		deadline := time.Now().Add(time.Second)
+		for {
+			if _, writes := w.snapshot(); writes == 1 {
+				break
+			}
+			if time.Now().After(deadline) {
+				t.Fatal("entries never flushed after the writer unblocked")
+			}
+			time.Sleep(time.Millisecond)
+		}
+	})
+
+	t.Run("writer keeps up", func(t *testing.T) {
+		w := &countingWriter{}
+		b, err := NewBatchWriter(w, 1<<20, 0)
+		if err != nil {
+			t.Fatal(err)
+		}
+		b.Write(entry)
+
+		if err := b.CloseContext(context.Background()); err != nil {
+			t.Errorf("CloseContext() = %v, want nil", err)
+		}
+		if got, _ := w.snapshot(); got != string(entry) {
+			t.Errorf("written = %q, want %q", got, entry)
+		}
+	})
+}
+
+func TestNewBatchesFileOutput(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "app.log")
+	l, err := New(Config{ServiceName: "test", Format: "json", FilePath: path, FileBatchBytes: 1 << 20})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	l.Info("batched")
+
+	if data, _ := os.ReadFile(path); len(data) != 0 {
+		t.Errorf("entry written before the batch was full: %q", data)
+	}
+
+	if err := l.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	data, _ := os.ReadFile(path)
+	if !strings.Contains(string(data), `"msg":"batched"`) {
+		t.Errorf("log file = %q, want the batched entry", data)
+	}
+}
+
+func BenchmarkBatchWriter(b *testing.B) {
+	benchmarks := []struct {
+		name  string
+		batch bool
+	}{
+		{name: "direct"},
+		{name: "batched", batch: true},
+	}
+
+	for _, bm := range benchmarks {
+		b.Run(bm.name, func(b *testing.B) {
+			file, err := os.Create(filepath.Join(b.TempDir(), "bench.log"))
+			if err != nil {
+				b.Fatal(err)
+			}
+			defer file.Close()
+
+			var w io.Writer = file
+			if bm.batch {
+				batch, err := NewBatchWriter(file, 64<<10, time.Second)
+				if err != nil {
+					b.Fatal(err)
+				}
+				defer batch.Close()
+				w = batch
+			}
+
+			logger := slog.New(slog.NewJSONHandler(w, nil))
+
+			b.ReportAllocs()
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				logger.Info("metric", slog.String("name", "requests"), slog.Int("value", i))
+			}
+		})
+	}
+}
+
+func TestLoggerCloseContext(t *testing.T) {
+	w := &blockingWriter{release: make(chan struct{})}
+	batch, err := NewBatchWriter(w, 1<<20, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	l := &Logger{Logger: slog.New(slog.NewJSONHandler(batch, nil)), batch: batch}
+	for i := 0; i < 4; i++ {
+		l.Info("queued")
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+
+	unflushed, err := l.CloseContext(ctx)
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("CloseContext() err = %v, want context.DeadlineExceeded", err)
+	}
+	if unflushed != 4 {
+		t.Errorf("unflushed = %d, want 4", unflushed)
+	}
+
+	close(w.release)
+}
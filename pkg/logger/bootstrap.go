@@ -0,0 +1,14 @@
+package logger
+
+import (
+	"log/slog"
+	"os"
+)
+
+// Bootstrap returns a minimal console JSON logger for use before the env is
+// loaded and the real logger can be built, so even configuration errors come
+// out structured.
+func Bootstrap() *slog.Logger {
+	return slog.New(newHandler(os.Stdout, "json", slog.LevelInfo)).
+		With(slog.String("service", serviceNameOrDefault("")))
+}
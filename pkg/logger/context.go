@@ -0,0 +1,28 @@
+package logger
+
+import (
+	"context"
+	"log/slog"
+)
+
+// contextHandler flags entries logged with an already cancelled context.
+// Cancellation never suppresses an entry, it is only recorded for diagnostics.
+type contextHandler struct {
+	slog.Handler
+}
+
+func (h contextHandler) Handle(ctx context.Context, rec slog.Record) error {
+	if ctx != nil && ctx.Err() != nil {
+		rec = rec.Clone()
+		rec.AddAttrs(slog.Bool("ctx_cancelled", true))
+	}
+	return h.Handler.Handle(ctx, rec)
+}
+
+func (h contextHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return contextHandler{h.Handler.WithAttrs(attrs)}
+}
+
+func (h contextHandler) WithGroup(name string) slog.Handler {
+	return contextHandler{h.Handler.WithGroup(name)}
+}
@@ -0,0 +1,57 @@
+package logger
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"log/slog"
+	"strings"
+	"testing"
+)
+
+func TestContextHandlerFlagsCancelledContext(t *testing.T) {
+	cancelled, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	tests := []struct {
+		name          string
+		ctx           context.Context
+		wantCancelled bool
+	}{
+		{name: "live context", ctx: context.Background()},
+		{name: "cancelled context", ctx: cancelled, wantCancelled: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var out bytes.Buffer
+			l := slog.New(newHandler(&out, "json", slog.LevelInfo)).With(slog.String("service", "orders"))
+
+			l.InfoContext(tt.ctx, "request completed", slog.Int("status", 200))
+
+			var entry map[string]interface{}
+			if err := json.Unmarshal(out.Bytes(), &entry); err != nil {
+				t.Fatalf("entry not written: %q: %v", out.String(), err)
+			}
+
+			if entry["msg"] != "request completed" || entry["service"] != "orders" {
+				t.Errorf("entry = %v, want the message with its attributes", entry)
+			}
+			if _, flagged := entry["ctx_cancelled"]; flagged != tt.wantCancelled {
+				t.Errorf("ctx_cancelled present = %v, want %v", flagged, tt.wantCancelled)
+			}
+		})
+	}
+}
+
+func TestContextHandlerTextFormat(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	var out bytes.Buffer
+	slog.New(newHandler(&out, "text", slog.LevelInfo)).WithGroup("req").InfoContext(ctx, "done")
+
+	if !strings.Contains(out.String(), "req.ctx_cancelled=true") {
+		t.Errorf("text entry = %q, want the ctx_cancelled flag", out.String())
+	}
+}
@@ -0,0 +1,55 @@
+package logger
+
+import (
+	"encoding/json"
+	"log/slog"
+	"sync/atomic"
+)
+
+// Encoder marshals the structured values attached to JSON entries, e.g. maps
+// and structs, so a faster implementation like jsoniter can be plugged in.
+// response.Encoder implementations satisfy it. Turn HTML escaping off to keep
+// the output identical to the default one.
+type Encoder interface {
+	Marshal(v interface{}) ([]byte, error)
+}
+
+type encoderBox struct {
+	Encoder
+}
+
+// encoder is nil by default, leaving the encoding to log/slog itself.
+var encoder atomic.Pointer[encoderBox]
+
+// SetEncoder replaces the JSON encoder of every logger, nil restores the
+// standard library one.
+func SetEncoder(e Encoder) {
+	if e == nil {
+		encoder.Store(nil)
+		return
+	}
+	encoder.Store(&encoderBox{e})
+}
+
+// encodeAttr marshals a's value with the configured Encoder, slog writes the
+// result as is. Errors and levels keep their own formatting and values the
+// Encoder fails on are left to slog.
+func encodeAttr(a slog.Attr) slog.Attr {
+	box := encoder.Load()
+	if box == nil || a.Value.Kind() != slog.KindAny {
+		return a
+	}
+
+	switch a.Value.Any().(type) {
+	case error, slog.Level:
+		return a
+	}
+
+	data, err := box.Marshal(a.Value.Any())
+	if err != nil {
+		return a
+	}
+
+	a.Value = slog.AnyValue(json.RawMessage(data))
+	return a
+}
@@ -0,0 +1,105 @@
+package logger
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"io"
+	"log/slog"
+	"testing"
+)
+
+// streamEncoder stands in for an adapter around a third party implementation,
+// with HTML escaping off like slog's own encoding.
+type streamEncoder struct {
+	calls int
+}
+
+func (e *streamEncoder) Marshal(v interface{}) ([]byte, error) {
+	e.calls++
+
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	enc.SetEscapeHTML(false)
+	if err := enc.Encode(v); err != nil {
+		return nil, err
+	}
+	return bytes.TrimSuffix(buf.Bytes(), []byte("\n")), nil
+}
+
+type encoderOrder struct {
+	ID    int      `json:"id"`
+	Owner string   `json:"owner"`
+	Tags  []string `json:"tags,omitempty"`
+}
+
+// logEncoderEntry logs an entry exercising escaping, nesting, groups and errors.
+func logEncoderEntry(w io.Writer) {
+	l := slog.New(newHandler(w, "json", slog.LevelInfo))
+	l.Info("order created",
+		slog.Any("order", encoderOrder{ID: 42, Owner: "<Ada & Linus>", Tags: []string{"a", "b"}}),
+		slog.Any("meta", map[string]interface{}{"active": true, "manager": nil}),
+		slog.Group("http", slog.Any("headers", []string{"Accept"})),
+		slog.Any("error", errors.New("retrying")),
+		slog.Int("attempt", 2),
+	)
+}
+
+func TestEncoderOutputParity(t *testing.T) {
+	defer SetEncoder(nil)
+
+	var std bytes.Buffer
+	logEncoderEntry(&std)
+
+	enc := &streamEncoder{}
+	SetEncoder(enc)
+	var custom bytes.Buffer
+	logEncoderEntry(&custom)
+
+	// the time differs, everything after it has to match byte for byte
+	_, stdRest, _ := bytes.Cut(std.Bytes(), []byte(`"level"`))
+	_, customRest, _ := bytes.Cut(custom.Bytes(), []byte(`"level"`))
+	if !bytes.Equal(stdRest, customRest) {
+		t.Errorf("outputs differ:\n std:    %s\n custom: %s", std.String(), custom.String())
+	}
+	if enc.calls != 3 {
+		t.Errorf("encoder calls = %d, want 3 for the order, meta and headers values", enc.calls)
+	}
+}
+
+func TestEncoderTextFormatUnaffected(t *testing.T) {
+	defer SetEncoder(nil)
+
+	enc := &streamEncoder{}
+	SetEncoder(enc)
+
+	var out bytes.Buffer
+	slog.New(newHandler(&out, "text", slog.LevelInfo)).Info("order created", slog.Any("order", encoderOrder{ID: 42}))
+
+	if enc.calls != 0 {
+		t.Errorf("encoder calls = %d, want 0 for the text format", enc.calls)
+	}
+}
+
+func BenchmarkLogJSON(b *testing.B) {
+	defer SetEncoder(nil)
+
+	encoders := []struct {
+		name    string
+		encoder Encoder
+	}{
+		{name: "std"},
+		{name: "stream", encoder: &streamEncoder{}},
+	}
+
+	for _, e := range encoders {
+		b.Run(e.name, func(b *testing.B) {
+			SetEncoder(e.encoder)
+			b.ReportAllocs()
+
+			for i := 0; i < b.N; i++ {
+				logEncoderEntry(io.Discard)
+			}
+		})
+	}
+}
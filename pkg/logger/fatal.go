@@ -0,0 +1,45 @@
+package logger
+
+import (
+	"context"
+	"log/slog"
+	"os"
+	"sync"
+)
+
+// LevelFatal ranks above slog.LevelError and is used by Fatal.
+const LevelFatal = slog.Level(12)
+
+// exitFunc ends the process after Fatal, swapped out in tests.
+var exitFunc = os.Exit
+
+var (
+	fatalHooksMu sync.Mutex
+	fatalHooks   []func()
+)
+
+// OnFatal registers fn to run before the process exits through Fatal, e.g.
+// to flush spans or close sockets that deferred calls would have handled.
+// Hooks run in registration order.
+func OnFatal(fn func()) {
+	fatalHooksMu.Lock()
+	defer fatalHooksMu.Unlock()
+
+	fatalHooks = append(fatalHooks, fn)
+}
+
+// Fatal logs msg at LevelFatal, runs the OnFatal hooks, closes the logger and exits with status 1.
+func (l *Logger) Fatal(msg string, args ...any) {
+	l.Log(context.Background(), LevelFatal, msg, args...)
+
+	fatalHooksMu.Lock()
+	hooks := append([]func(){}, fatalHooks...)
+	fatalHooksMu.Unlock()
+
+	for _, hook := range hooks {
+		hook()
+	}
+
+	l.Close()
+	exitFunc(1)
+}
@@ -0,0 +1,42 @@
+package logger
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+func TestFatalRunsHooksBeforeExit(t *testing.T) {
+	var calls []string
+	defer func(hooks []func(), exit func(int)) {
+		fatalHooks, exitFunc = hooks, exit
+	}(fatalHooks, exitFunc)
+
+	fatalHooks = nil
+	OnFatal(func() { calls = append(calls, "flush spans") })
+	OnFatal(func() { calls = append(calls, "close sockets") })
+	exitFunc = func(code int) { calls = append(calls, "exit "+strconv.Itoa(code)) }
+
+	path := filepath.Join(t.TempDir(), "app.log")
+	l, err := New(Config{ServiceName: "orders", Format: "json", FilePath: path, FileBatchBytes: 1 << 20})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	l.Fatal("database unreachable")
+
+	if want := []string{"flush spans", "close sockets", "exit 1"}; !reflect.DeepEqual(calls, want) {
+		t.Errorf("calls = %v, want %v", calls, want)
+	}
+
+	written, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(written), "database unreachable") {
+		t.Errorf("fatal entry not flushed before exit: %q", written)
+	}
+}
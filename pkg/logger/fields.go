@@ -0,0 +1,60 @@
+package logger
+
+import (
+	"log/slog"
+	"sort"
+)
+
+// WithContext returns a logger whose entries, audit ones included, carry
+// fields. Chained calls merge their fields: nested map[string]interface{}
+// values are merged key by key instead of the later map replacing the earlier
+// one, any other value under the same key is replaced.
+func (l *Logger) WithContext(fields map[string]interface{}) *Logger {
+	base, auditBase := l.base, l.auditBase
+	if base == nil {
+		base, auditBase = l.Logger, l.audit
+	}
+
+	merged := mergeFields(l.fields, fields)
+
+	keys := make([]string, 0, len(merged))
+	for key := range merged {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	attrs := make([]any, 0, len(keys))
+	for _, key := range keys {
+		attrs = append(attrs, slog.Any(key, merged[key]))
+	}
+
+	scoped := *l
+	scoped.base = base
+	scoped.auditBase = auditBase
+	scoped.fields = merged
+	scoped.Logger = base.With(attrs...)
+	if auditBase != nil {
+		scoped.audit = auditBase.With(attrs...)
+	}
+	return &scoped
+}
+
+// mergeFields deep-merges src into a copy of dst, neither is modified.
+func mergeFields(dst, src map[string]interface{}) map[string]interface{} {
+	merged := make(map[string]interface{}, len(dst)+len(src))
+	for key, value := range dst {
+		merged[key] = value
+	}
+
+	for key, value := range src {
+		existing, existingIsMap := merged[key].(map[string]interface{})
+		nested, nestedIsMap := value.(map[string]interface{})
+		if existingIsMap && nestedIsMap {
+			merged[key] = mergeFields(existing, nested)
+			continue
+		}
+		merged[key] = value
+	}
+
+	return merged
+}
@@ -0,0 +1,117 @@
+package logger
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"log/slog"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestWithContextMergesFields(t *testing.T) {
+	tests := []struct {
+		name   string
+		first  map[string]interface{}
+		second map[string]interface{}
+		want   map[string]interface{}
+	}{
+		{
+			name:   "nested maps are merged",
+			first:  map[string]interface{}{"user": map[string]interface{}{"id": "42"}},
+			second: map[string]interface{}{"user": map[string]interface{}{"role": "admin"}},
+			want:   map[string]interface{}{"user": map[string]interface{}{"id": "42", "role": "admin"}},
+		},
+		{
+			name: "deeply nested maps are merged",
+			first: map[string]interface{}{"http": map[string]interface{}{
+				"request": map[string]interface{}{"method": "GET"},
+			}},
+			second: map[string]interface{}{"http": map[string]interface{}{
+				"request": map[string]interface{}{"path": "/users"},
+			}},
+			want: map[string]interface{}{"http": map[string]interface{}{
+				"request": map[string]interface{}{"method": "GET", "path": "/users"},
+			}},
+		},
+		{
+			name:   "later scalar wins",
+			first:  map[string]interface{}{"attempt": 1.0, "job": "sync"},
+			second: map[string]interface{}{"attempt": 2.0},
+			want:   map[string]interface{}{"attempt": 2.0, "job": "sync"},
+		},
+		{
+			name:   "scalar replaces map",
+			first:  map[string]interface{}{"user": map[string]interface{}{"id": "42"}},
+			second: map[string]interface{}{"user": "anonymous"},
+			want:   map[string]interface{}{"user": "anonymous"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var out bytes.Buffer
+			l := &Logger{Logger: slog.New(slog.NewJSONHandler(&out, nil))}
+
+			l.WithContext(tt.first).WithContext(tt.second).Info("merged")
+
+			var entry map[string]interface{}
+			if err := json.Unmarshal(out.Bytes(), &entry); err != nil {
+				t.Fatalf("decoding %q: %v", out.String(), err)
+			}
+			for _, key := range []string{"time", "level", "msg"} {
+				delete(entry, key)
+			}
+
+			if !reflect.DeepEqual(entry, tt.want) {
+				t.Errorf("fields = %v, want %v", entry, tt.want)
+			}
+		})
+	}
+}
+
+func TestWithContextLeavesParentUnchanged(t *testing.T) {
+	var out bytes.Buffer
+	parent := (&Logger{Logger: slog.New(slog.NewJSONHandler(&out, nil))}).
+		WithContext(map[string]interface{}{"user": map[string]interface{}{"id": "42"}})
+
+	parent.WithContext(map[string]interface{}{"user": map[string]interface{}{"role": "admin"}})
+	parent.Info("parent")
+
+	var entry map[string]interface{}
+	if err := json.Unmarshal(out.Bytes(), &entry); err != nil {
+		t.Fatal(err)
+	}
+
+	want := map[string]interface{}{"id": "42"}
+	if !reflect.DeepEqual(entry["user"], want) {
+		t.Errorf("parent user = %v, want %v", entry["user"], want)
+	}
+}
+
+func TestWithContextAppliesToAudit(t *testing.T) {
+	var audit bytes.Buffer
+	l, err := New(Config{ServiceName: "orders", Format: "json", FilePath: filepath.Join(t.TempDir(), "app.log"), AuditWriter: &audit})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer l.Close()
+
+	scoped := l.WithContext(map[string]interface{}{"request_id": "req-1"}).
+		ForTenant("acme").
+		WithContext(map[string]interface{}{"trace_id": "trace-1"})
+	scoped.Audit(context.Background(), "login", nil)
+
+	var entry map[string]interface{}
+	if err := json.Unmarshal(audit.Bytes(), &entry); err != nil {
+		t.Fatalf("decoding %q: %v", audit.String(), err)
+	}
+
+	want := map[string]interface{}{"request_id": "req-1", "trace_id": "trace-1", "tenant_id": "acme", "stream": AuditStream}
+	for key, value := range want {
+		if entry[key] != value {
+			t.Errorf("%s = %v, want %v", key, entry[key], value)
+		}
+	}
+}
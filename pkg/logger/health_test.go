@@ -0,0 +1,63 @@
+package logger
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestHealthCheck(t *testing.T) {
+	tests := []struct {
+		name    string
+		breakFn func(t *testing.T, path string)
+		wantErr bool
+	}{
+		{name: "writable file"},
+		{
+			name: "file replaced by a directory",
+			breakFn: func(t *testing.T, path string) {
+				if err := os.Remove(path); err != nil {
+					t.Fatal(err)
+				}
+				if err := os.Mkdir(path, 0o755); err != nil {
+					t.Fatal(err)
+				}
+			},
+			wantErr: true,
+		},
+		{
+			name: "file removed",
+			breakFn: func(t *testing.T, path string) {
+				if err := os.RemoveAll(filepath.Dir(path)); err != nil {
+					t.Fatal(err)
+				}
+			},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "logs", "app.log")
+			l, err := New(Config{ServiceName: "orders", Format: "json", FilePath: path})
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer l.Close()
+
+			if tt.breakFn != nil {
+				tt.breakFn(t, path)
+			}
+
+			if err := l.HealthCheck(); (err != nil) != tt.wantErr {
+				t.Errorf("HealthCheck() err = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestHealthCheckWithoutFile(t *testing.T) {
+	if err := DiscardLogger().HealthCheck(); err != nil {
+		t.Errorf("HealthCheck() err = %v, want nil", err)
+	}
+}
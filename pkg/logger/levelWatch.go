@@ -0,0 +1,111 @@
+package logger
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/fsnotify/fsnotify"
+)
+
+// LevelEnvKey is the key WatchLevelFile looks up in the config file.
+const LevelEnvKey = "LOG_LEVEL"
+
+// ParseLevel turns debug, info, warn, error or fatal (any case) into a level.
+func ParseLevel(value string) (slog.Level, error) {
+	value = strings.TrimSpace(value)
+	if strings.EqualFold(value, "fatal") {
+		return LevelFatal, nil
+	}
+
+	var level slog.Level
+	if err := level.UnmarshalText([]byte(value)); err != nil {
+		return 0, fmt.Errorf("invalid log level %q", value)
+	}
+	return level, nil
+}
+
+// readLevelFile returns the LOG_LEVEL value of a KEY=VALUE file, "" when unset.
+func readLevelFile(path string) (string, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return "", err
+	}
+	defer file.Close()
+
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		key, value, ok := strings.Cut(scanner.Text(), "=")
+		if ok && strings.TrimSpace(key) == LevelEnvKey {
+			return strings.Trim(strings.TrimSpace(value), `"'`), nil
+		}
+	}
+	return "", scanner.Err()
+}
+
+// reloadLevel applies the level found in path, keeping the current one when
+// the file can't be read or holds no valid level.
+func (l *Logger) reloadLevel(path string) {
+	value, err := readLevelFile(path)
+	if err != nil || value == "" {
+		return
+	}
+
+	level, err := ParseLevel(value)
+	if err != nil {
+		l.Warn("ignoring log level from config file", slog.String("path", path), slog.String("error", err.Error()))
+		return
+	}
+
+	if level != l.Level() {
+		l.SetLevel(level)
+		l.Info("log level changed", slog.String("path", path), slog.String("level", level.String()))
+	}
+}
+
+// WatchLevelFile applies LOG_LEVEL from the KEY=VALUE file at path now and
+// every time the file changes, until ctx is done. This allows changing the
+// verbosity by editing e.g. a mounted ConfigMap, without a restart. The
+// directory is watched since ConfigMap updates swap the file through a symlink.
+func (l *Logger) WatchLevelFile(ctx context.Context, path string) error {
+	watcher, err := fsnotify.NewWatcher()
+	if err != nil {
+		return err
+	}
+
+	if err := watcher.Add(filepath.Dir(path)); err != nil {
+		watcher.Close()
+		return err
+	}
+
+	l.reloadLevel(path)
+
+	go func() {
+		defer watcher.Close()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case event, ok := <-watcher.Events:
+				if !ok {
+					return
+				}
+				if event.Has(fsnotify.Write) || event.Has(fsnotify.Create) {
+					l.reloadLevel(path)
+				}
+			case err, ok := <-watcher.Errors:
+				if !ok {
+					return
+				}
+				l.Warn("log level watcher failed", slog.String("error", err.Error()))
+			}
+		}
+	}()
+
+	return nil
+}
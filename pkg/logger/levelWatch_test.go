@@ -0,0 +1,95 @@
+package logger
+
+import (
+	"context"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestParseLevel(t *testing.T) {
+	tests := []struct {
+		value   string
+		want    slog.Level
+		wantErr bool
+	}{
+		{value: "debug", want: slog.LevelDebug},
+		{value: "INFO", want: slog.LevelInfo},
+		{value: " warn ", want: slog.LevelWarn},
+		{value: "error", want: slog.LevelError},
+		{value: "Fatal", want: LevelFatal},
+		{value: "verbose", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.value, func(t *testing.T) {
+			got, err := ParseLevel(tt.value)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseLevel(%q) err = %v, wantErr %v", tt.value, err, tt.wantErr)
+			}
+			if !tt.wantErr && got != tt.want {
+				t.Errorf("ParseLevel(%q) = %v, want %v", tt.value, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestWatchLevelFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "logging.env")
+	if err := os.WriteFile(path, []byte("SERVICE=orders\nLOG_LEVEL=warn\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	l, err := New(Config{ServiceName: "orders", Format: "json", FilePath: filepath.Join(t.TempDir(), "app.log")})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer l.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	if err := l.WatchLevelFile(ctx, path); err != nil {
+		t.Fatal(err)
+	}
+	if l.Level() != slog.LevelWarn {
+		t.Fatalf("initial level = %v, want %v", l.Level(), slog.LevelWarn)
+	}
+
+	waitForLevel := func(want slog.Level) {
+		t.Helper()
+		deadline := time.Now().Add(2 * time.Second)
+		for l.Level() != want {
+			if time.Now().After(deadline) {
+				t.Fatalf("level = %v, want %v after the file changed", l.Level(), want)
+			}
+			time.Sleep(5 * time.Millisecond)
+		}
+	}
+
+	if err := os.WriteFile(path, []byte("LOG_LEVEL=\"debug\"\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	waitForLevel(slog.LevelDebug)
+
+	// an invalid level keeps the current one
+	if err := os.WriteFile(path, []byte("LOG_LEVEL=verbose\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	time.Sleep(50 * time.Millisecond)
+	if l.Level() != slog.LevelDebug {
+		t.Errorf("level = %v after an invalid value, want %v", l.Level(), slog.LevelDebug)
+	}
+
+	// ConfigMaps swap the file instead of writing to it
+	swapped := path + ".new"
+	if err := os.WriteFile(swapped, []byte("LOG_LEVEL=error\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Rename(swapped, path); err != nil {
+		t.Fatal(err)
+	}
+	waitForLevel(slog.LevelError)
+}
@@ -0,0 +1,342 @@
+package logger
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync/atomic"
+	"time"
+)
+
+// Config describes where and how a Logger writes.
+type Config struct {
+	ServiceName string
+
+	// ServiceEnv and ServiceVersion are added to every entry when not empty.
+	ServiceEnv     string
+	ServiceVersion string
+
+	Format   string // "json" or "text"
+	Level    slog.Level
+	Console  bool
+	FilePath string // no file output when empty
+
+	// FileBatchBytes, when set, batches file writes through a BatchWriter
+	// flushing at that many bytes, every FileBatchInterval and on Close.
+	FileBatchBytes    int
+	FileBatchInterval time.Duration
+
+	// ConsoleFormat overrides Format for the console only, e.g. readable text
+	// on the console while the file gets JSON. Format is used when empty.
+	ConsoleFormat string
+
+	// ErrorLogFilePath additionally receives ERROR and FATAL entries, like
+	// nginx's error log next to the combined one. Disabled when empty.
+	ErrorLogFilePath string
+
+	// AuditWriter receives audit entries instead of the main output when set.
+	AuditWriter io.Writer
+}
+
+// Logger is a slog.Logger tied to the resources it writes to. Its context
+// fields can be added to through WithContext.
+type Logger struct {
+	*slog.Logger
+	audit     *slog.Logger
+	level     *slog.LevelVar
+	file      *os.File
+	batch     *BatchWriter
+	errorFile *os.File
+
+	// base, auditBase and fields back WithContext, the bases are the loggers
+	// before any fields
+	base      *slog.Logger
+	auditBase *slog.Logger
+	fields    map[string]interface{}
+
+	writeFailures *atomic.Uint64
+}
+
+// ConfigForEnv returns sensible defaults for the development, production and
+// test environments. Unknown environments get the development defaults.
+func ConfigForEnv(serviceName, env string) Config {
+	serviceName = serviceNameOrDefault(serviceName)
+
+	switch strings.ToLower(env) {
+	case "production", "prod":
+		return Config{
+			ServiceName: serviceName,
+			ServiceEnv:  env,
+			Format:      "json",
+			Level:       slog.LevelInfo,
+			Console:     true,
+			FilePath:    filepath.Join("logs", serviceName+".log"),
+		}
+	case "test":
+		return Config{
+			ServiceName: serviceName,
+			ServiceEnv:  env,
+			Format:      "text",
+			Level:       slog.LevelWarn,
+			Console:     true,
+		}
+	default:
+		return Config{
+			ServiceName: serviceName,
+			ServiceEnv:  env,
+			Format:      "text",
+			Level:       slog.LevelDebug,
+			Console:     true,
+		}
+	}
+}
+
+// NewForEnv builds a Logger with the defaults of ConfigForEnv.
+func NewForEnv(serviceName, env string) (*Logger, error) {
+	return New(ConfigForEnv(serviceName, env))
+}
+
+// ErrNoOutput is returned by New when cfg enables neither the console nor a
+// file. Use DiscardLogger for a logger that intentionally writes nowhere.
+var ErrNoOutput = errors.New("logger has no output, enable Console or set FilePath")
+
+// New builds a Logger from cfg. Every entry carries the service name, which
+// defaults to the go.mod module name when empty.
+func New(cfg Config) (*Logger, error) {
+	cfg.ServiceName = serviceNameOrDefault(cfg.ServiceName)
+	l := &Logger{level: new(slog.LevelVar), writeFailures: new(atomic.Uint64)}
+	l.level.Set(cfg.Level)
+
+	consoleFormat := cfg.ConsoleFormat
+	if consoleFormat == "" {
+		consoleFormat = cfg.Format
+	}
+
+	// writers sharing a format share a handler, so every entry is formatted once per format
+	var formats []string
+	writers := make(map[string][]io.Writer)
+	addWriter := func(format string, w io.Writer) {
+		if _, ok := writers[format]; !ok {
+			formats = append(formats, format)
+		}
+		writers[format] = append(writers[format], w)
+	}
+
+	if cfg.Console {
+		addWriter(consoleFormat, os.Stdout)
+	}
+
+	if cfg.FilePath != "" {
+		file, err := openLogFile(cfg.FilePath)
+		if err != nil {
+			return nil, err
+		}
+
+		l.file = file
+
+		if cfg.FileBatchBytes != 0 {
+			batch, err := NewBatchWriter(file, cfg.FileBatchBytes, cfg.FileBatchInterval)
+			if err != nil {
+				l.Close()
+				return nil, err
+			}
+
+			l.batch = batch
+			addWriter(cfg.Format, batch)
+		} else {
+			addWriter(cfg.Format, file)
+		}
+	}
+
+	if len(formats) == 0 {
+		return nil, ErrNoOutput
+	}
+
+	if cfg.ErrorLogFilePath != "" {
+		file, err := openLogFile(cfg.ErrorLogFilePath)
+		if err != nil {
+			l.Close()
+			return nil, err
+		}
+
+		l.errorFile = file
+	}
+
+	static := []any{slog.String("service", cfg.ServiceName)}
+	if cfg.ServiceEnv != "" {
+		static = append(static, slog.String("env", cfg.ServiceEnv))
+	}
+	if cfg.ServiceVersion != "" {
+		static = append(static, slog.String("version", cfg.ServiceVersion))
+	}
+
+	outputs := func(level slog.Leveler) slog.Handler {
+		if len(formats) == 1 {
+			return newHandler(l.countFailures(io.MultiWriter(writers[formats[0]]...)), formats[0], level)
+		}
+
+		handlers := make(fanoutHandler, 0, len(formats))
+		for _, format := range formats {
+			handlers = append(handlers, newHandler(l.countFailures(io.MultiWriter(writers[format]...)), format, level))
+		}
+		return handlers
+	}
+
+	handler := outputs(l.level)
+	if l.errorFile != nil {
+		errorLevel := minLevel{Leveler: l.level, floor: slog.LevelError}
+		handler = fanoutHandler{handler, newHandler(l.countFailures(l.errorFile), cfg.Format, errorLevel)}
+	}
+	l.Logger = slog.New(handler).With(static...)
+
+	auditHandler := outputs(slog.LevelInfo)
+	if cfg.AuditWriter != nil {
+		auditHandler = newHandler(l.countFailures(cfg.AuditWriter), "json", slog.LevelInfo)
+	}
+	l.audit = slog.New(auditHandler).
+		With(append(static, slog.String("stream", AuditStream))...)
+
+	return l, nil
+}
+
+// minLevel is a Leveler that never goes below floor.
+type minLevel struct {
+	slog.Leveler
+	floor slog.Level
+}
+
+func (m minLevel) Level() slog.Level {
+	return max(m.Leveler.Level(), m.floor)
+}
+
+func newHandler(w io.Writer, format string, level slog.Leveler) slog.Handler {
+	opts := &slog.HandlerOptions{
+		Level: level,
+		ReplaceAttr: func(groups []string, a slog.Attr) slog.Attr {
+			// slog would print LevelFatal as ERROR+4
+			if a.Key == slog.LevelKey && len(groups) == 0 {
+				if lvl, ok := a.Value.Any().(slog.Level); ok && lvl == LevelFatal {
+					a.Value = slog.StringValue("FATAL")
+				}
+			}
+			if format == "json" {
+				a = encodeAttr(a)
+			}
+			return a
+		},
+	}
+	if format == "json" {
+		return contextHandler{slog.NewJSONHandler(w, opts)}
+	}
+	return contextHandler{slog.NewTextHandler(w, opts)}
+}
+
+// Level returns the minimum level currently logged.
+func (l *Logger) Level() slog.Level {
+	if l.level == nil {
+		return slog.LevelInfo
+	}
+	return l.level.Level()
+}
+
+// SetLevel changes the minimum level logged at runtime, for l and every
+// logger derived from it. It is a no-op on loggers not built by New.
+func (l *Logger) SetLevel(level slog.Level) {
+	if l.level == nil {
+		return
+	}
+	l.level.Set(level)
+}
+
+// DiscardLogger returns a Logger dropping every entry, audit ones included, e.g.
+// for tests or tools that want no log output.
+func DiscardLogger() *Logger {
+	discard := slog.New(slog.NewTextHandler(io.Discard, nil))
+
+	return &Logger{
+		Logger:        discard,
+		audit:         discard,
+		level:         new(slog.LevelVar),
+		writeFailures: new(atomic.Uint64),
+	}
+}
+
+// openLogFile opens path for appending, creating it and its directory if needed.
+func openLogFile(path string) (*os.File, error) {
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return nil, fmt.Errorf("creating log directory: %w", err)
+	}
+
+	file, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o644)
+	if err != nil {
+		return nil, fmt.Errorf("opening log file: %w", err)
+	}
+
+	return file, nil
+}
+
+// Close flushes and releases the log files, if any.
+func (l *Logger) Close() error {
+	_, err := l.CloseContext(context.Background())
+	return err
+}
+
+// CloseContext is Close bounded by ctx, so a blocked log file can't hang
+// shutdown. When ctx ends before the batched entries are written it returns
+// how many were left behind along with an *UnflushedError, the files then stay
+// open for the flush still running in the background.
+func (l *Logger) CloseContext(ctx context.Context) (unflushed int, err error) {
+	var batchErr error
+	if l.batch != nil {
+		batchErr = l.batch.CloseContext(ctx)
+
+		var unflushedErr *UnflushedError
+		if errors.As(batchErr, &unflushedErr) {
+			return int(unflushedErr.Entries), batchErr
+		}
+	}
+
+	return 0, errors.Join(batchErr, closeLogFile(l.file), closeLogFile(l.errorFile))
+}
+
+func closeLogFile(file *os.File) error {
+	if file == nil {
+		return nil
+	}
+
+	if err := file.Sync(); err != nil {
+		file.Close()
+		return err
+	}
+	return file.Close()
+}
+
+// HealthCheck reports whether the log file can still be written, so a full
+// disk or a permission change doesn't silently drop logs. Loggers without a
+// file are always healthy.
+func (l *Logger) HealthCheck() error {
+	if l.file == nil {
+		return nil
+	}
+
+	if _, err := l.file.Stat(); err != nil {
+		return fmt.Errorf("log file unavailable: %w", err)
+	}
+
+	probe, err := os.OpenFile(l.file.Name(), os.O_WRONLY|os.O_APPEND, 0)
+	if err != nil {
+		return fmt.Errorf("log file not writable: %w", err)
+	}
+
+	if _, err := probe.Write(nil); err != nil {
+		probe.Close()
+		return fmt.Errorf("log file not writable: %w", err)
+	}
+
+	return probe.Close()
+}
@@ -0,0 +1,298 @@
+package logger
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestConfigForEnv(t *testing.T) {
+	tests := []struct {
+		env          string
+		wantFormat   string
+		wantLevel    slog.Level
+		wantFilePath string
+	}{
+		{env: "development", wantFormat: "text", wantLevel: slog.LevelDebug},
+		{env: "production", wantFormat: "json", wantLevel: slog.LevelInfo, wantFilePath: filepath.Join("logs", "orders.log")},
+		{env: "PROD", wantFormat: "json", wantLevel: slog.LevelInfo, wantFilePath: filepath.Join("logs", "orders.log")},
+		{env: "test", wantFormat: "text", wantLevel: slog.LevelWarn},
+		{env: "staging", wantFormat: "text", wantLevel: slog.LevelDebug},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.env, func(t *testing.T) {
+			cfg := ConfigForEnv("orders", tt.env)
+
+			if cfg.Format != tt.wantFormat {
+				t.Errorf("Format = %q, want %q", cfg.Format, tt.wantFormat)
+			}
+			if cfg.Level != tt.wantLevel {
+				t.Errorf("Level = %v, want %v", cfg.Level, tt.wantLevel)
+			}
+			if cfg.FilePath != tt.wantFilePath {
+				t.Errorf("FilePath = %q, want %q", cfg.FilePath, tt.wantFilePath)
+			}
+			if !cfg.Console {
+				t.Error("console output disabled")
+			}
+			if cfg.ServiceName != "orders" || cfg.ServiceEnv != tt.env {
+				t.Errorf("service = %q/%q, want orders/%s", cfg.ServiceName, cfg.ServiceEnv, tt.env)
+			}
+		})
+	}
+}
+
+func TestNewForEnv(t *testing.T) {
+	l, err := NewForEnv("orders", "test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer l.Close()
+
+	if l.Level() != slog.LevelWarn {
+		t.Errorf("Level() = %v, want %v", l.Level(), slog.LevelWarn)
+	}
+}
+
+func TestNewServiceFields(t *testing.T) {
+	tests := []struct {
+		name        string
+		env         string
+		version     string
+		wantEnv     interface{}
+		wantVersion interface{}
+	}{
+		{name: "both set", env: "production", version: "v1.2.3", wantEnv: "production", wantVersion: "v1.2.3"},
+		{name: "only env", env: "staging", wantEnv: "staging"},
+		{name: "neither set"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "app.log")
+			l, err := New(Config{
+				ServiceName:    "orders",
+				ServiceEnv:     tt.env,
+				ServiceVersion: tt.version,
+				Format:         "json",
+				FilePath:       path,
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			l.Info("first")
+			l.Warn("second")
+			if err := l.Close(); err != nil {
+				t.Fatal(err)
+			}
+
+			written, err := os.ReadFile(path)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			lines := strings.Split(strings.TrimSpace(string(written)), "\n")
+			if len(lines) != 2 {
+				t.Fatalf("got %d entries, want 2", len(lines))
+			}
+
+			for _, line := range lines {
+				var entry map[string]interface{}
+				if err := json.Unmarshal([]byte(line), &entry); err != nil {
+					t.Fatal(err)
+				}
+
+				if entry["env"] != tt.wantEnv {
+					t.Errorf("env = %v, want %v", entry["env"], tt.wantEnv)
+				}
+				if entry["version"] != tt.wantVersion {
+					t.Errorf("version = %v, want %v", entry["version"], tt.wantVersion)
+				}
+			}
+		})
+	}
+}
+
+func TestErrorLogFile(t *testing.T) {
+	dir := t.TempDir()
+	mainPath := filepath.Join(dir, "combined.log")
+	errorPath := filepath.Join(dir, "error.log")
+
+	l, err := New(Config{ServiceName: "orders", Format: "json", FilePath: mainPath, ErrorLogFilePath: errorPath})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	l.Info("request completed")
+	l.Warn("slow request")
+	l.Error("payment failed")
+	l.Log(context.Background(), LevelFatal, "database unreachable")
+	if err := l.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		msg        string
+		wantMain   bool
+		wantErrLog bool
+	}{
+		{msg: "request completed", wantMain: true},
+		{msg: "slow request", wantMain: true},
+		{msg: "payment failed", wantMain: true, wantErrLog: true},
+		{msg: "database unreachable", wantMain: true, wantErrLog: true},
+	}
+
+	combined, err := os.ReadFile(mainPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	errorLog, err := os.ReadFile(errorPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.msg, func(t *testing.T) {
+			if got := strings.Contains(string(combined), tt.msg); got != tt.wantMain {
+				t.Errorf("in combined log = %v, want %v", got, tt.wantMain)
+			}
+			if got := strings.Contains(string(errorLog), tt.msg); got != tt.wantErrLog {
+				t.Errorf("in error log = %v, want %v", got, tt.wantErrLog)
+			}
+		})
+	}
+}
+
+func TestErrorLogFollowsLevel(t *testing.T) {
+	errorPath := filepath.Join(t.TempDir(), "error.log")
+	l, err := New(Config{ServiceName: "orders", Format: "json", FilePath: filepath.Join(t.TempDir(), "app.log"), ErrorLogFilePath: errorPath})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	l.SetLevel(LevelFatal)
+	l.Error("filtered by level")
+	l.Close()
+
+	if data, _ := os.ReadFile(errorPath); len(data) != 0 {
+		t.Errorf("error log = %q, want nothing below the logger level", data)
+	}
+}
+
+func TestConsoleFormat(t *testing.T) {
+	tests := []struct {
+		name          string
+		consoleFormat string
+		wantConsole   string
+	}{
+		{name: "text console, json file", consoleFormat: "text", wantConsole: "text"},
+		{name: "defaults to Format", wantConsole: "json"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			consolePath := filepath.Join(dir, "console.log")
+			console, err := os.Create(consolePath)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			// the console output is os.Stdout, read at New
+			stdout := os.Stdout
+			os.Stdout = console
+			l, err := New(Config{
+				Level:         slog.LevelInfo,
+				Format:        "json",
+				ConsoleFormat: tt.consoleFormat,
+				Console:       true,
+				FilePath:      filepath.Join(dir, "app.log"),
+				ServiceName:   "orders",
+			})
+			os.Stdout = stdout
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			l.Info("order created", slog.Int("id", 7))
+			if err := l.Close(); err != nil {
+				t.Fatal(err)
+			}
+			console.Close()
+
+			fileOut, err := os.ReadFile(filepath.Join(dir, "app.log"))
+			if err != nil {
+				t.Fatal(err)
+			}
+			var entry map[string]any
+			if err := json.Unmarshal(fileOut, &entry); err != nil {
+				t.Fatalf("file entry is not JSON: %v: %s", err, fileOut)
+			}
+			if entry["msg"] != "order created" {
+				t.Errorf("file msg = %v, want %q", entry["msg"], "order created")
+			}
+
+			consoleOut, err := os.ReadFile(consolePath)
+			if err != nil {
+				t.Fatal(err)
+			}
+			line := strings.TrimSpace(string(consoleOut))
+			got := "text"
+			if json.Valid([]byte(line)) {
+				got = "json"
+			}
+			if got != tt.wantConsole {
+				t.Errorf("console format = %s, want %s: %s", got, tt.wantConsole, line)
+			}
+			if !strings.Contains(line, "order created") {
+				t.Errorf("console output misses the entry: %s", line)
+			}
+		})
+	}
+}
+
+func TestNewWithoutOutput(t *testing.T) {
+	tests := []struct {
+		name    string
+		cfg     Config
+		wantErr error
+	}{
+		{name: "no console, no file", cfg: Config{ServiceName: "orders"}, wantErr: ErrNoOutput},
+		{name: "only an audit writer", cfg: Config{ServiceName: "orders", AuditWriter: io.Discard}, wantErr: ErrNoOutput},
+		{name: "file", cfg: Config{ServiceName: "orders", FilePath: filepath.Join(t.TempDir(), "app.log")}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			l, err := New(tt.cfg)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("New() err = %v, want %v", err, tt.wantErr)
+			}
+			if err == nil {
+				l.Close()
+			}
+		})
+	}
+}
+
+func TestDiscardLogger(t *testing.T) {
+	l := DiscardLogger()
+
+	l.Info("dropped")
+	l.Audit(context.Background(), "login", map[string]interface{}{"user_id": "42"})
+
+	if got := l.WriteFailureCount(); got != 0 {
+		t.Errorf("WriteFailureCount() = %d, want 0", got)
+	}
+	if err := l.Close(); err != nil {
+		t.Errorf("Close() = %v", err)
+	}
+}
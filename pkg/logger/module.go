@@ -0,0 +1,46 @@
+package logger
+
+import (
+	"bufio"
+	"os"
+	"path"
+	"strings"
+)
+
+// defaultServiceName is used when neither a name nor a go.mod is available.
+const defaultServiceName = "app"
+
+// serviceNameOrDefault falls back to the module name from ./go.mod when name is empty.
+func serviceNameOrDefault(name string) string {
+	if name != "" {
+		return name
+	}
+
+	if module := getModuleNameFromGoMod("go.mod"); module != "" {
+		return module
+	}
+
+	return defaultServiceName
+}
+
+// getModuleNameFromGoMod returns the last element of the module path declared
+// in the go.mod at goModPath, e.g. "go-boilerplate" for
+// github.com/himtar/go-boilerplate. It returns "" if the file can't be read.
+func getModuleNameFromGoMod(goModPath string) string {
+	file, err := os.Open(goModPath)
+	if err != nil {
+		return ""
+	}
+	defer file.Close()
+
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if module, ok := strings.CutPrefix(line, "module "); ok {
+			module = strings.Trim(strings.TrimSpace(module), `"`)
+			return path.Base(module)
+		}
+	}
+
+	return ""
+}
@@ -0,0 +1,77 @@
+package logger
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestGetModuleNameFromGoMod(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		want    string
+	}{
+		{name: "module path", content: "module github.com/acme/orders\n\ngo 1.21\n", want: "orders"},
+		{name: "quoted module path", content: "module \"github.com/acme/billing\"\n", want: "billing"},
+		{name: "single element", content: "// service\nmodule payments\n", want: "payments"},
+		{name: "no module line", content: "go 1.21\n", want: ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "go.mod")
+			if err := os.WriteFile(path, []byte(tt.content), 0o644); err != nil {
+				t.Fatal(err)
+			}
+
+			if got := getModuleNameFromGoMod(path); got != tt.want {
+				t.Errorf("getModuleNameFromGoMod() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+
+	if got := getModuleNameFromGoMod(filepath.Join(t.TempDir(), "missing.mod")); got != "" {
+		t.Errorf("missing go.mod = %q, want empty", got)
+	}
+}
+
+func TestServiceNameOrDefault(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "go.mod"), []byte("module github.com/acme/orders\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	wd, err := os.Getwd()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Chdir(dir); err != nil {
+		t.Fatal(err)
+	}
+	defer os.Chdir(wd)
+
+	tests := []struct {
+		name string
+		in   string
+		want string
+	}{
+		{name: "explicit name wins", in: "billing", want: "billing"},
+		{name: "empty name falls back to go.mod", in: "", want: "orders"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := serviceNameOrDefault(tt.in); got != tt.want {
+				t.Errorf("serviceNameOrDefault(%q) = %q, want %q", tt.in, got, tt.want)
+			}
+		})
+	}
+
+	if err := os.Remove(filepath.Join(dir, "go.mod")); err != nil {
+		t.Fatal(err)
+	}
+	if got := serviceNameOrDefault(""); got != defaultServiceName {
+		t.Errorf("without go.mod = %q, want %q", got, defaultServiceName)
+	}
+}
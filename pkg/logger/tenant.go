@@ -0,0 +1,40 @@
+package logger
+
+import (
+	"context"
+	"log/slog"
+)
+
+type contextKey struct{}
+
+// ForTenant returns a logger whose entries, audit ones included, carry tenant_id.
+func (l *Logger) ForTenant(tenantID string) *Logger {
+	tenant := slog.String("tenant_id", tenantID)
+
+	scoped := *l
+	scoped.Logger = l.Logger.With(tenant)
+	scoped.audit = l.audit.With(tenant)
+	if l.base != nil {
+		scoped.base = l.base.With(tenant)
+	}
+	if l.auditBase != nil {
+		scoped.auditBase = l.auditBase.With(tenant)
+	}
+	return &scoped
+}
+
+// NewContext returns a copy of ctx carrying l.
+func NewContext(ctx context.Context, l *Logger) context.Context {
+	return context.WithValue(ctx, contextKey{}, l)
+}
+
+// FromContext returns the logger stored by NewContext, or one writing through
+// slog.Default() when there is none.
+func FromContext(ctx context.Context) *Logger {
+	if l, ok := ctx.Value(contextKey{}).(*Logger); ok {
+		return l
+	}
+
+	def := slog.Default()
+	return &Logger{Logger: def, audit: def.With(slog.String("stream", AuditStream))}
+}
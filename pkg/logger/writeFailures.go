@@ -0,0 +1,39 @@
+package logger
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"sync/atomic"
+)
+
+// failureCountingWriter counts failed writes, which slog would otherwise drop
+// silently, and reports them on stderr.
+type failureCountingWriter struct {
+	io.Writer
+	failures *atomic.Uint64
+}
+
+func (w failureCountingWriter) Write(p []byte) (int, error) {
+	n, err := w.Writer.Write(p)
+	if err != nil {
+		w.failures.Add(1)
+		fmt.Fprintf(os.Stderr, "logger: write failed: %v\n", err)
+	}
+	return n, err
+}
+
+// countFailures wraps w so its failed writes show up in WriteFailureCount.
+func (l *Logger) countFailures(w io.Writer) io.Writer {
+	return failureCountingWriter{Writer: w, failures: l.writeFailures}
+}
+
+// WriteFailureCount returns how many log writes failed so far, e.g. because
+// the disk is full, for metrics or a readiness check. Derived loggers share
+// the count of the logger they were built from.
+func (l *Logger) WriteFailureCount() uint64 {
+	if l.writeFailures == nil {
+		return 0
+	}
+	return l.writeFailures.Load()
+}
@@ -0,0 +1,52 @@
+package logger
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"testing"
+)
+
+// failingWriter fails every write like a full disk would.
+type failingWriter struct{}
+
+func (failingWriter) Write([]byte) (int, error) {
+	return 0, errors.New("no space left on device")
+}
+
+func TestWriteFailureCount(t *testing.T) {
+	l, err := New(Config{
+		ServiceName: "orders",
+		Format:      "json",
+		FilePath:    filepath.Join(t.TempDir(), "app.log"),
+		AuditWriter: failingWriter{},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer l.Close()
+
+	l.Info("written fine")
+	if got := l.WriteFailureCount(); got != 0 {
+		t.Fatalf("WriteFailureCount() = %d before any failure, want 0", got)
+	}
+
+	l.Audit(context.Background(), "login", nil)
+	l.ForTenant("acme").Audit(context.Background(), "logout", nil)
+	if got := l.WriteFailureCount(); got != 2 {
+		t.Errorf("WriteFailureCount() = %d after two failed audit writes, want 2", got)
+	}
+
+	// the file going away makes the main output fail too
+	l.file.Close()
+	l.Info("lost")
+	if got := l.WriteFailureCount(); got != 3 {
+		t.Errorf("WriteFailureCount() = %d after a failed file write, want 3", got)
+	}
+}
+
+func TestWriteFailureCountWithoutNew(t *testing.T) {
+	if got := FromContext(context.Background()).WriteFailureCount(); got != 0 {
+		t.Errorf("WriteFailureCount() = %d, want 0", got)
+	}
+}
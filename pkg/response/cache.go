@@ -0,0 +1,28 @@
+package response
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+)
+
+// WithCacheControl marks the response as cacheable for maxAge, by shared
+// caches too when public is set. A maxAge of zero or less disables caching.
+// Call it before sending the response.
+func WithCacheControl(w http.ResponseWriter, maxAge time.Duration, public bool) {
+	header := w.Header()
+
+	if maxAge <= 0 {
+		header.Set("Cache-Control", "no-store")
+		header.Set("Expires", "0")
+		return
+	}
+
+	scope := "private"
+	if public {
+		scope = "public"
+	}
+
+	header.Set("Cache-Control", fmt.Sprintf("%s, max-age=%d", scope, int(maxAge.Seconds())))
+	header.Set("Expires", time.Now().Add(maxAge).UTC().Format(http.TimeFormat))
+}
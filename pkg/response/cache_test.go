@@ -0,0 +1,52 @@
+package response
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestWithCacheControl(t *testing.T) {
+	tests := []struct {
+		name         string
+		maxAge       time.Duration
+		public       bool
+		wantCache    string
+		wantNoExpiry bool
+	}{
+		{name: "public hour", maxAge: time.Hour, public: true, wantCache: "public, max-age=3600"},
+		{name: "private minute", maxAge: time.Minute, wantCache: "private, max-age=60"},
+		{name: "sub-second rounds down", maxAge: 1500 * time.Millisecond, public: true, wantCache: "public, max-age=1"},
+		{name: "zero disables caching", maxAge: 0, public: true, wantCache: "no-store", wantNoExpiry: true},
+		{name: "negative disables caching", maxAge: -time.Minute, wantCache: "no-store", wantNoExpiry: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := httptest.NewRecorder()
+			before := time.Now().Truncate(time.Second)
+			WithCacheControl(rec, tt.maxAge, tt.public)
+
+			if got := rec.Header().Get("Cache-Control"); got != tt.wantCache {
+				t.Errorf("Cache-Control = %q, want %q", got, tt.wantCache)
+			}
+
+			expires := rec.Header().Get("Expires")
+			if tt.wantNoExpiry {
+				if expires != "0" {
+					t.Errorf("Expires = %q, want 0", expires)
+				}
+				return
+			}
+
+			at, err := http.ParseTime(expires)
+			if err != nil {
+				t.Fatalf("Expires = %q is not an HTTP date: %v", expires, err)
+			}
+			if want := before.Add(tt.maxAge); at.Before(want.Add(-time.Second)) || at.After(want.Add(2*time.Second)) {
+				t.Errorf("Expires = %v, want about %v", at, want)
+			}
+		})
+	}
+}
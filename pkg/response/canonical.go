@@ -0,0 +1,76 @@
+package response
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+)
+
+// CanonicalEncoder produces byte-stable output whatever Inner does with map
+// ordering: Inner's output is decoded again and re-encoded with
+// encoding/json, which sorts object keys. encoding/json alone is already
+// stable, this is for plugged in encoders that aren't. Inner defaults to
+// StdEncoder.
+type CanonicalEncoder struct {
+	Inner Encoder
+}
+
+func (c CanonicalEncoder) inner() Encoder {
+	if c.Inner == nil {
+		return StdEncoder{}
+	}
+	return c.Inner
+}
+
+func (c CanonicalEncoder) Marshal(v interface{}) ([]byte, error) {
+	data, err := c.inner().Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	return canonicalize(data)
+}
+
+func (c CanonicalEncoder) NewEncoder(w io.Writer) StreamEncoder {
+	return &canonicalStreamEncoder{w: w, enc: c}
+}
+
+type canonicalStreamEncoder struct {
+	w              io.Writer
+	enc            CanonicalEncoder
+	prefix, indent string
+}
+
+func (s *canonicalStreamEncoder) SetIndent(prefix, indent string) {
+	s.prefix, s.indent = prefix, indent
+}
+
+func (s *canonicalStreamEncoder) Encode(v interface{}) error {
+	data, err := s.enc.Marshal(v)
+	if err != nil {
+		return err
+	}
+
+	if s.prefix != "" || s.indent != "" {
+		var buf bytes.Buffer
+		if err := json.Indent(&buf, data, s.prefix, s.indent); err != nil {
+			return err
+		}
+		data = buf.Bytes()
+	}
+
+	// match json.Encoder, which terminates every value with a newline
+	_, err = s.w.Write(append(data, '\n'))
+	return err
+}
+
+// canonicalize re-encodes a JSON document with sorted object keys, keeping numbers verbatim.
+func canonicalize(data []byte) ([]byte, error) {
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+
+	var v interface{}
+	if err := decoder.Decode(&v); err != nil {
+		return nil, err
+	}
+	return json.Marshal(v)
+}
@@ -0,0 +1,110 @@
+package response
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+// shuffledEncoder writes object keys in whatever order Go map iteration
+// yields, like an encoder that doesn't sort them.
+type shuffledEncoder struct{}
+
+func (shuffledEncoder) Marshal(v interface{}) ([]byte, error) {
+	return shuffle(v)
+}
+
+// NewEncoder is unused, CanonicalEncoder streams through Marshal.
+func (shuffledEncoder) NewEncoder(w io.Writer) StreamEncoder {
+	return &marshalStream{w: w}
+}
+
+func shuffle(v interface{}) ([]byte, error) {
+	m, ok := v.(map[string]interface{})
+	if !ok {
+		return json.Marshal(v)
+	}
+
+	var buf bytes.Buffer
+	buf.WriteByte('{')
+	first := true
+	for key, value := range m {
+		if !first {
+			buf.WriteByte(',')
+		}
+		first = false
+
+		k, _ := json.Marshal(key)
+		inner, err := shuffle(value)
+		if err != nil {
+			return nil, err
+		}
+		buf.Write(k)
+		buf.WriteByte(':')
+		buf.Write(inner)
+	}
+	buf.WriteByte('}')
+	return buf.Bytes(), nil
+}
+
+func TestCanonicalEncoderIsStable(t *testing.T) {
+	payload := map[string]interface{}{
+		"zeta": 1, "alpha": 2, "mid": 3, "beta": 4, "omega": 5, "gamma": 6,
+		"nested": map[string]interface{}{"z": true, "a": false, "m": nil},
+		"big":    json.Number("12345678901234567890"),
+	}
+
+	tests := []struct {
+		name    string
+		encoder Encoder
+	}{
+		{name: "default inner", encoder: CanonicalEncoder{}},
+		{name: "unordered inner", encoder: CanonicalEncoder{Inner: shuffledEncoder{}}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			first, err := tt.encoder.Marshal(payload)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			for i := 0; i < 20; i++ {
+				again, err := tt.encoder.Marshal(payload)
+				if err != nil {
+					t.Fatal(err)
+				}
+				if !bytes.Equal(first, again) {
+					t.Fatalf("marshal %d differs:\n%s\n%s", i, first, again)
+				}
+			}
+
+			want := `{"alpha":2,"beta":4,"big":12345678901234567890,"gamma":6,"mid":3,"nested":{"a":false,"m":null,"z":true},"omega":5,"zeta":1}`
+			if string(first) != want {
+				t.Errorf("Marshal() = %s, want %s", first, want)
+			}
+		})
+	}
+}
+
+func TestCanonicalEncoderThroughSend(t *testing.T) {
+	SetEncoder(CanonicalEncoder{Inner: shuffledEncoder{}})
+	defer SetEncoder(nil)
+
+	send := func() string {
+		rec := httptest.NewRecorder()
+		SendJSON(rec, http.StatusOK, map[string]interface{}{"b": 1, "a": 2, "c": 3, "d": 4})
+		return rec.Body.String()
+	}
+
+	first := send()
+	if second := send(); first != second {
+		t.Errorf("responses differ: %q and %q", first, second)
+	}
+	if want := "{\"a\":2,\"b\":1,\"c\":3,\"d\":4}\n"; first != want {
+		t.Errorf("body = %q, want %q", first, want)
+	}
+}
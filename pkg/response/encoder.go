@@ -0,0 +1,55 @@
+package response
+
+import (
+	"encoding/json"
+	"io"
+	"sync"
+)
+
+// StreamEncoder writes JSON values to a stream, *json.Encoder satisfies it.
+type StreamEncoder interface {
+	Encode(v interface{}) error
+	SetIndent(prefix, indent string)
+}
+
+// Encoder is the JSON implementation used by the package, so a faster one
+// like jsoniter can be plugged in with a thin adapter.
+type Encoder interface {
+	Marshal(v interface{}) ([]byte, error)
+	NewEncoder(w io.Writer) StreamEncoder
+}
+
+// StdEncoder is the encoding/json backed Encoder used by default.
+type StdEncoder struct{}
+
+func (StdEncoder) Marshal(v interface{}) ([]byte, error) {
+	return json.Marshal(v)
+}
+
+func (StdEncoder) NewEncoder(w io.Writer) StreamEncoder {
+	return json.NewEncoder(w)
+}
+
+var (
+	encoderMu sync.RWMutex
+	encoder   Encoder = StdEncoder{}
+)
+
+// SetEncoder replaces the JSON encoder, nil restores the standard library one.
+func SetEncoder(e Encoder) {
+	if e == nil {
+		e = StdEncoder{}
+	}
+
+	encoderMu.Lock()
+	defer encoderMu.Unlock()
+
+	encoder = e
+}
+
+func currentEncoder() Encoder {
+	encoderMu.RLock()
+	defer encoderMu.RUnlock()
+
+	return encoder
+}
@@ -0,0 +1,138 @@
+package response
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+// marshalEncoder is an alternative Encoder built on Marshal alone, standing
+// in for an adapter around a third party implementation.
+type marshalEncoder struct{}
+
+func (marshalEncoder) Marshal(v interface{}) ([]byte, error) {
+	return json.Marshal(v)
+}
+
+func (marshalEncoder) NewEncoder(w io.Writer) StreamEncoder {
+	return &marshalStream{w: w}
+}
+
+type marshalStream struct {
+	w              io.Writer
+	prefix, indent string
+}
+
+func (s *marshalStream) SetIndent(prefix, indent string) {
+	s.prefix, s.indent = prefix, indent
+}
+
+func (s *marshalStream) Encode(v interface{}) error {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+
+	if s.prefix != "" || s.indent != "" {
+		var indented bytes.Buffer
+		if err := json.Indent(&indented, data, s.prefix, s.indent); err != nil {
+			return err
+		}
+		data = indented.Bytes()
+	}
+
+	_, err = s.w.Write(append(data, '\n'))
+	return err
+}
+
+// encoderPayload exercises escaping, nesting and omitted fields.
+var encoderPayload = map[string]interface{}{
+	"id":    42,
+	"name":  "<Ada & Linus>",
+	"tags":  []string{"a", "b"},
+	"price": 9.99,
+	"owner": map[string]interface{}{"active": true, "manager": nil},
+}
+
+func TestEncoderOutputParity(t *testing.T) {
+	defer SetEncoder(nil)
+
+	send := func(e Encoder, pretty bool) string {
+		SetEncoder(e)
+		SetPretty(pretty)
+		defer SetPretty(false)
+
+		rec := httptest.NewRecorder()
+		Send(rec, http.StatusOK, "found", encoderPayload)
+		return rec.Body.String()
+	}
+
+	stream := func(e Encoder) string {
+		SetEncoder(e)
+
+		items := make(chan interface{}, 2)
+		items <- encoderPayload
+		items <- []int{1, 2, 3}
+		close(items)
+
+		rec := httptest.NewRecorder()
+		if err := SendNDJSON(context.Background(), rec, items); err != nil {
+			t.Fatal(err)
+		}
+		return rec.Body.String()
+	}
+
+	tests := []struct {
+		name   string
+		render func(e Encoder) string
+	}{
+		{name: "compact", render: func(e Encoder) string { return send(e, false) }},
+		{name: "pretty", render: func(e Encoder) string { return send(e, true) }},
+		{name: "ndjson", render: stream},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			want := tt.render(StdEncoder{})
+			if got := tt.render(marshalEncoder{}); got != want {
+				t.Errorf("output differs between encoders\nstd:     %q\nmarshal: %q", want, got)
+			}
+		})
+	}
+}
+
+func TestSetEncoderNilRestoresStd(t *testing.T) {
+	SetEncoder(marshalEncoder{})
+	SetEncoder(nil)
+
+	if _, ok := currentEncoder().(StdEncoder); !ok {
+		t.Errorf("encoder = %T, want StdEncoder", currentEncoder())
+	}
+}
+
+func BenchmarkSendJSON(b *testing.B) {
+	defer SetEncoder(nil)
+
+	encoders := []struct {
+		name    string
+		encoder Encoder
+	}{
+		{name: "std", encoder: StdEncoder{}},
+		{name: "marshal", encoder: marshalEncoder{}},
+	}
+
+	for _, e := range encoders {
+		b.Run(e.name, func(b *testing.B) {
+			SetEncoder(e.encoder)
+			b.ReportAllocs()
+
+			for i := 0; i < b.N; i++ {
+				SendJSON(httptest.NewRecorder(), http.StatusOK, encoderPayload)
+			}
+		})
+	}
+}
@@ -0,0 +1,98 @@
+package response
+
+import (
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// DefaultLocale is used when none of the request's languages has a catalog.
+var DefaultLocale = "en"
+
+var (
+	catalogsMu sync.RWMutex
+	catalogs   = make(map[string]map[string]string)
+)
+
+// RegisterMessages adds the messages of m to the catalog of locale.
+func RegisterMessages(locale string, m map[string]string) {
+	catalogsMu.Lock()
+	defer catalogsMu.Unlock()
+
+	locale = strings.ToLower(locale)
+	if catalogs[locale] == nil {
+		catalogs[locale] = make(map[string]string, len(m))
+	}
+
+	for key, message := range m {
+		catalogs[locale][key] = message
+	}
+}
+
+// Localize resolves key against the catalogs using the request's Accept-Language
+// header, falling back to DefaultLocale and finally to the key itself.
+func Localize(r *http.Request, key string) string {
+	catalogsMu.RLock()
+	defer catalogsMu.RUnlock()
+
+	for _, locale := range acceptedLocales(r.Header.Get("Accept-Language")) {
+		if message, ok := catalogs[locale][key]; ok {
+			return message
+		}
+
+		// en-GB falls back to en
+		if base, _, found := strings.Cut(locale, "-"); found {
+			if message, ok := catalogs[base][key]; ok {
+				return message
+			}
+		}
+	}
+
+	if message, ok := catalogs[strings.ToLower(DefaultLocale)][key]; ok {
+		return message
+	}
+
+	return key
+}
+
+// SendLocalized sends the standard envelope with the message for key in the client's language.
+func SendLocalized(w http.ResponseWriter, r *http.Request, statusCode int, key string, data interface{}) {
+	Send(w, statusCode, Localize(r, key), data)
+}
+
+// acceptedLocales returns the locales of an Accept-Language header ordered by quality.
+func acceptedLocales(header string) []string {
+	type weighted struct {
+		locale string
+		q      float64
+	}
+
+	var locales []weighted
+	for _, part := range strings.Split(header, ",") {
+		tag, params, _ := strings.Cut(strings.TrimSpace(part), ";")
+		if tag == "" || tag == "*" {
+			continue
+		}
+
+		q := 1.0
+		if value, ok := strings.CutPrefix(strings.TrimSpace(params), "q="); ok {
+			if parsed, err := strconv.ParseFloat(value, 64); err == nil {
+				q = parsed
+			}
+		}
+
+		locales = append(locales, weighted{locale: strings.ToLower(tag), q: q})
+	}
+
+	sort.SliceStable(locales, func(i, j int) bool {
+		return locales[i].q > locales[j].q
+	})
+
+	result := make([]string, len(locales))
+	for i, l := range locales {
+		result[i] = l.locale
+	}
+	return result
+}
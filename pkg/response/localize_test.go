@@ -0,0 +1,45 @@
+package response
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestSendLocalized(t *testing.T) {
+	RegisterMessages("en", map[string]string{"order.created": "Order created"})
+	RegisterMessages("fr", map[string]string{"order.created": "Commande créée"})
+
+	tests := []struct {
+		name           string
+		acceptLanguage string
+		key            string
+		want           string
+	}{
+		{name: "english", acceptLanguage: "en-US,en;q=0.9", key: "order.created", want: "Order created"},
+		{name: "french", acceptLanguage: "fr", key: "order.created", want: "Commande créée"},
+		{name: "regional falls back to base", acceptLanguage: "fr-CA", key: "order.created", want: "Commande créée"},
+		{name: "quality order wins", acceptLanguage: "en;q=0.5, fr;q=0.8", key: "order.created", want: "Commande créée"},
+		{name: "unknown locale falls back to default", acceptLanguage: "de", key: "order.created", want: "Order created"},
+		{name: "unknown key is returned as is", acceptLanguage: "fr", key: "order.missing", want: "order.missing"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodPost, "/orders", nil)
+			req.Header.Set("Accept-Language", tt.acceptLanguage)
+			rec := httptest.NewRecorder()
+
+			SendLocalized(rec, req, http.StatusCreated, tt.key, nil)
+
+			var res Response
+			if err := json.Unmarshal(rec.Body.Bytes(), &res); err != nil {
+				t.Fatal(err)
+			}
+			if res.Message != tt.want {
+				t.Errorf("message = %q, want %q", res.Message, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,84 @@
+package response
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"strings"
+	"unicode/utf8"
+)
+
+// maskedValue replaces masked values that can't be partially shown.
+const maskedValue = "***"
+
+// SendMasked sends the standard envelope with the values of maskFields masked
+// anywhere in data, nested objects and arrays included, e.g. an email becomes
+// u***@example.com. Field names are matched against the JSON keys,
+// case-insensitively.
+func SendMasked(w http.ResponseWriter, statusCode int, message string, data interface{}, maskFields []string) {
+	// round-trip through JSON so struct tags decide the field names, as in the response
+	raw, err := json.Marshal(data)
+	if err != nil {
+		http.Error(w, "Internal Server Error !", http.StatusInternalServerError)
+		return
+	}
+
+	dec := json.NewDecoder(bytes.NewReader(raw))
+	dec.UseNumber()
+
+	var generic interface{}
+	if err := dec.Decode(&generic); err != nil {
+		http.Error(w, "Internal Server Error !", http.StatusInternalServerError)
+		return
+	}
+
+	fields := make(map[string]bool, len(maskFields))
+	for _, field := range maskFields {
+		fields[strings.ToLower(field)] = true
+	}
+
+	Send(w, statusCode, message, maskFieldsIn(generic, fields))
+}
+
+// maskFieldsIn walks a decoded JSON value masking the values under fields.
+func maskFieldsIn(value interface{}, fields map[string]bool) interface{} {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		for key, child := range v {
+			if fields[strings.ToLower(key)] {
+				v[key] = maskValue(child)
+				continue
+			}
+			v[key] = maskFieldsIn(child, fields)
+		}
+	case []interface{}:
+		for i, child := range v {
+			v[i] = maskFieldsIn(child, fields)
+		}
+	}
+	return value
+}
+
+// maskValue keeps the first character of strings, and the domain of emails.
+// Anything else is replaced entirely, nulls stay null.
+func maskValue(value interface{}) interface{} {
+	switch v := value.(type) {
+	case nil:
+		return nil
+	case string:
+		if local, domain, ok := strings.Cut(v, "@"); ok && local != "" {
+			return firstRune(local) + maskedValue + "@" + domain
+		}
+		if utf8.RuneCountInString(v) > 3 {
+			return firstRune(v) + maskedValue
+		}
+		return maskedValue
+	default:
+		return maskedValue
+	}
+}
+
+func firstRune(s string) string {
+	_, size := utf8.DecodeRuneInString(s)
+	return s[:size]
+}
@@ -0,0 +1,100 @@
+package response
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+type maskedAddress struct {
+	City  string `json:"city"`
+	Phone string `json:"phone"`
+}
+
+type maskedUser struct {
+	ID        int             `json:"id"`
+	Email     string          `json:"email"`
+	Name      string          `json:"name"`
+	SSN       *string         `json:"ssn"`
+	Age       int             `json:"age"`
+	Addresses []maskedAddress `json:"addresses"`
+}
+
+func TestSendMasked(t *testing.T) {
+	user := maskedUser{
+		ID:    7,
+		Email: "user@example.com",
+		Name:  "Ada",
+		Age:   36,
+		Addresses: []maskedAddress{
+			{City: "London", Phone: "+44 20 7946 0958"},
+			{City: "Paris", Phone: "+33 1 23 45 67 89"},
+		},
+	}
+
+	rec := httptest.NewRecorder()
+	SendMasked(rec, http.StatusOK, "", user, []string{"EMAIL", "phone", "ssn", "age", "name"})
+
+	var res struct {
+		Data map[string]interface{} `json:"data"`
+	}
+	if err := json.Unmarshal(rec.Body.Bytes(), &res); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name string
+		got  interface{}
+		want interface{}
+	}{
+		{name: "email keeps first letter and domain", got: res.Data["email"], want: "u***@example.com"},
+		{name: "short string fully masked", got: res.Data["name"], want: "***"},
+		{name: "number masked", got: res.Data["age"], want: "***"},
+		{name: "null stays null", got: res.Data["ssn"], want: nil},
+		{name: "unlisted field untouched", got: res.Data["id"], want: 7.0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if tt.got != tt.want {
+				t.Errorf("got %v, want %v", tt.got, tt.want)
+			}
+		})
+	}
+
+	addresses, _ := res.Data["addresses"].([]interface{})
+	for i, city := range []string{"London", "Paris"} {
+		address := addresses[i].(map[string]interface{})
+		if address["phone"] != "+***" {
+			t.Errorf("address %d phone = %v, want it masked", i, address["phone"])
+		}
+		if address["city"] != city {
+			t.Errorf("address %d city = %v, want %s untouched", i, address["city"], city)
+		}
+	}
+
+	if user.Email != "user@example.com" {
+		t.Error("SendMasked modified the caller's data")
+	}
+}
+
+func TestMaskValue(t *testing.T) {
+	tests := []struct {
+		in   interface{}
+		want interface{}
+	}{
+		{in: "jane.doe@corp.io", want: "j***@corp.io"},
+		{in: "@handle", want: "@***"},
+		{in: "élodie", want: "é***"},
+		{in: "abc", want: "***"},
+		{in: true, want: "***"},
+		{in: nil, want: nil},
+	}
+
+	for _, tt := range tests {
+		if got := maskValue(tt.in); got != tt.want {
+			t.Errorf("maskValue(%v) = %v, want %v", tt.in, got, tt.want)
+		}
+	}
+}
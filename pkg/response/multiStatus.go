@@ -0,0 +1,43 @@
+package response
+
+import (
+	"fmt"
+	"net/http"
+)
+
+// ItemResult is the outcome of one item of a batch request.
+type ItemResult struct {
+	ID     string      `json:"id,omitempty"`
+	Status int         `json:"status"`
+	Data   interface{} `json:"data,omitempty"`
+	Error  string      `json:"error,omitempty"`
+}
+
+// SendMultiStatus sends a 207 envelope listing every item with its own status
+// and error, e.g. for bulk creates. success is only true when every item
+// succeeded and the message counts the failures otherwise.
+func SendMultiStatus(w http.ResponseWriter, results []ItemResult) {
+	failed := 0
+	for _, result := range results {
+		if result.Status >= http.StatusBadRequest {
+			failed++
+		}
+	}
+
+	message := http.StatusText(http.StatusMultiStatus)
+	if failed > 0 {
+		message = fmt.Sprintf("%d of %d items failed", failed, len(results))
+	}
+
+	res := Response{
+		Success: failed == 0,
+		Message: message,
+		Data:    results,
+	}
+
+	if !res.Success {
+		res.TraceID = traceIDOf(w)
+	}
+
+	SendJSON(w, http.StatusMultiStatus, res)
+}
@@ -0,0 +1,74 @@
+package response
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestSendMultiStatus(t *testing.T) {
+	tests := []struct {
+		name        string
+		results     []ItemResult
+		wantSuccess bool
+		wantMessage string
+	}{
+		{
+			name: "all succeeded",
+			results: []ItemResult{
+				{ID: "1", Status: http.StatusCreated, Data: map[string]string{"sku": "A-1"}},
+				{ID: "2", Status: http.StatusCreated},
+			},
+			wantSuccess: true,
+			wantMessage: "Multi-Status",
+		},
+		{
+			name: "partial failure",
+			results: []ItemResult{
+				{ID: "1", Status: http.StatusCreated},
+				{ID: "2", Status: http.StatusConflict, Error: "sku already exists"},
+				{ID: "3", Status: http.StatusUnprocessableEntity, Error: "quantity must be positive"},
+			},
+			wantMessage: "2 of 3 items failed",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := httptest.NewRecorder()
+			SendMultiStatus(WithTraceID(rec, "trace-1"), tt.results)
+
+			if rec.Code != http.StatusMultiStatus {
+				t.Errorf("status = %d, want %d", rec.Code, http.StatusMultiStatus)
+			}
+
+			var res struct {
+				Success bool         `json:"success"`
+				Message string       `json:"message"`
+				Data    []ItemResult `json:"data"`
+				TraceID string       `json:"trace_id"`
+			}
+			if err := json.Unmarshal(rec.Body.Bytes(), &res); err != nil {
+				t.Fatal(err)
+			}
+
+			if res.Success != tt.wantSuccess || res.Message != tt.wantMessage {
+				t.Errorf("envelope = %v %q, want %v %q", res.Success, res.Message, tt.wantSuccess, tt.wantMessage)
+			}
+			if (res.TraceID != "") == tt.wantSuccess {
+				t.Errorf("trace_id = %q, want it only on partial failure", res.TraceID)
+			}
+
+			if len(res.Data) != len(tt.results) {
+				t.Fatalf("got %d items, want %d", len(res.Data), len(tt.results))
+			}
+			for i, item := range res.Data {
+				want := tt.results[i]
+				if item.ID != want.ID || item.Status != want.Status || item.Error != want.Error {
+					t.Errorf("item %d = %+v, want %+v", i, item, want)
+				}
+			}
+		})
+	}
+}
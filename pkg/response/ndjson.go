@@ -0,0 +1,60 @@
+package response
+
+import (
+	"context"
+	"errors"
+	"net/http"
+)
+
+// ndjsonFlushEvery bounds how many lines are written between flushes.
+const ndjsonFlushEvery = 100
+
+// SendNDJSON streams every value received on items as one JSON document per
+// line (application/x-ndjson) until items is closed or ctx is done. Output is
+// flushed whenever the channel is drained and at least every 100 lines.
+// A failed write or flush, usually the client going away, stops the stream
+// and is returned for the handler to log.
+func SendNDJSON(ctx context.Context, w http.ResponseWriter, items <-chan interface{}) error {
+	w.Header().Set("Content-Type", "application/x-ndjson")
+	w.WriteHeader(http.StatusOK)
+
+	rc := http.NewResponseController(w)
+	enc := currentEncoder().NewEncoder(w)
+	pending := 0
+
+	flush := func() error {
+		if pending == 0 {
+			return nil
+		}
+		pending = 0
+
+		// writers that can't flush just deliver the output at the end
+		if err := rc.Flush(); err != nil && !errors.Is(err, http.ErrNotSupported) {
+			return err
+		}
+		return nil
+	}
+
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case item, ok := <-items:
+			if !ok {
+				return flush()
+			}
+
+			// Encode terminates every document with a newline
+			if err := enc.Encode(item); err != nil {
+				return err
+			}
+			pending++
+
+			if len(items) == 0 || pending >= ndjsonFlushEvery {
+				if err := flush(); err != nil {
+					return err
+				}
+			}
+		}
+	}
+}
@@ -0,0 +1,164 @@
+package response
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"syscall"
+	"testing"
+)
+
+func TestSendNDJSON(t *testing.T) {
+	items := make(chan interface{}, 3)
+	items <- map[string]int{"id": 1}
+	items <- map[string]int{"id": 2}
+	items <- map[string]int{"id": 3}
+	close(items)
+
+	rec := httptest.NewRecorder()
+	if err := SendNDJSON(context.Background(), rec, items); err != nil {
+		t.Fatal(err)
+	}
+
+	if got := rec.Header().Get("Content-Type"); got != "application/x-ndjson" {
+		t.Errorf("Content-Type = %q, want application/x-ndjson", got)
+	}
+	if !rec.Flushed {
+		t.Error("stream was never flushed")
+	}
+
+	scanner := bufio.NewScanner(strings.NewReader(rec.Body.String()))
+	var ids []int
+	for scanner.Scan() {
+		var line map[string]int
+		if err := json.Unmarshal(scanner.Bytes(), &line); err != nil {
+			t.Fatalf("line %q does not parse on its own: %v", scanner.Text(), err)
+		}
+		ids = append(ids, line["id"])
+	}
+
+	if len(ids) != 3 || ids[0] != 1 || ids[1] != 2 || ids[2] != 3 {
+		t.Errorf("ids = %v, want [1 2 3]", ids)
+	}
+}
+
+func TestSendNDJSONStopsOnCancel(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	items := make(chan interface{})
+
+	done := make(chan error, 1)
+	go func() {
+		done <- SendNDJSON(ctx, httptest.NewRecorder(), items)
+	}()
+
+	items <- map[string]int{"id": 1}
+	cancel()
+
+	if err := <-done; !errors.Is(err, context.Canceled) {
+		t.Errorf("err = %v, want context.Canceled", err)
+	}
+}
+
+func TestSendNDJSONStopsOnWriteError(t *testing.T) {
+	items := make(chan interface{}, 1)
+	items <- map[string]int{"id": 1}
+
+	err := SendNDJSON(context.Background(), &brokenPipeWriter{ResponseRecorder: httptest.NewRecorder()}, items)
+	if err == nil {
+		t.Error("write error was swallowed")
+	}
+}
+
+// goneClientWriter accepts okWrites writes, then fails writes and flushes
+// like a connection the client dropped mid-stream.
+type goneClientWriter struct {
+	*httptest.ResponseRecorder
+	okWrites int
+	writes   int
+	flushErr error
+}
+
+func (g *goneClientWriter) Write(b []byte) (int, error) {
+	g.writes++
+	if g.writes > g.okWrites {
+		return 0, syscall.EPIPE
+	}
+	return g.ResponseRecorder.Write(b)
+}
+
+func (g *goneClientWriter) FlushError() error {
+	if g.flushErr != nil {
+		return g.flushErr
+	}
+	g.ResponseRecorder.Flush()
+	return nil
+}
+
+func TestSendNDJSONStopsMidStream(t *testing.T) {
+	tests := []struct {
+		name       string
+		okWrites   int
+		flushErr   error
+		wantWrites int
+	}{
+		{name: "write fails", okWrites: 2, wantWrites: 3},
+		{name: "flush fails", okWrites: 10, flushErr: syscall.ECONNRESET, wantWrites: 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// an unbuffered feed flushes after every line
+			items := make(chan interface{})
+			w := &goneClientWriter{ResponseRecorder: httptest.NewRecorder(), okWrites: tt.okWrites, flushErr: tt.flushErr}
+
+			done := make(chan error, 1)
+			go func() {
+				done <- SendNDJSON(context.Background(), w, items)
+			}()
+
+			var err error
+			sent := 0
+		feed:
+			for ; sent < 10; sent++ {
+				select {
+				case items <- map[string]int{"id": sent}:
+				case err = <-done:
+					break feed
+				}
+			}
+			if sent == 10 {
+				close(items)
+				err = <-done
+			}
+
+			if !errors.Is(err, syscall.EPIPE) && !errors.Is(err, syscall.ECONNRESET) {
+				t.Fatalf("err = %v, want the write or flush error", err)
+			}
+			if w.writes != tt.wantWrites {
+				t.Errorf("writes = %d, want %d, the stream kept going after the failure", w.writes, tt.wantWrites)
+			}
+		})
+	}
+}
+
+func TestSendNDJSONWithoutFlusher(t *testing.T) {
+	items := make(chan interface{}, 1)
+	items <- map[string]int{"id": 1}
+	close(items)
+
+	// a writer that can't flush still gets the whole stream
+	var w struct{ http.ResponseWriter }
+	rec := httptest.NewRecorder()
+	w.ResponseWriter = rec
+
+	if err := SendNDJSON(context.Background(), w, items); err != nil {
+		t.Fatalf("SendNDJSON() = %v", err)
+	}
+	if got := strings.TrimSpace(rec.Body.String()); got != `{"id":1}` {
+		t.Errorf("body = %q, want the item", got)
+	}
+}
@@ -0,0 +1,26 @@
+package response
+
+import (
+	"math"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// SendRateLimited sends a 429 envelope with the X-RateLimit-Limit,
+// X-RateLimit-Remaining, X-RateLimit-Reset (unix seconds) and Retry-After
+// (seconds) headers clients use to back off.
+func SendRateLimited(w http.ResponseWriter, limit, remaining int, reset time.Time) {
+	retryAfter := int(math.Ceil(time.Until(reset).Seconds()))
+	if retryAfter < 0 {
+		retryAfter = 0
+	}
+
+	header := w.Header()
+	header.Set("X-RateLimit-Limit", strconv.Itoa(limit))
+	header.Set("X-RateLimit-Remaining", strconv.Itoa(remaining))
+	header.Set("X-RateLimit-Reset", strconv.FormatInt(reset.Unix(), 10))
+	header.Set("Retry-After", strconv.Itoa(retryAfter))
+
+	Send(w, http.StatusTooManyRequests, http.StatusText(http.StatusTooManyRequests), nil)
+}
@@ -0,0 +1,49 @@
+package response
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+)
+
+func TestSendRateLimited(t *testing.T) {
+	now := time.Now()
+
+	tests := []struct {
+		name           string
+		limit          int
+		remaining      int
+		reset          time.Time
+		wantRetryAfter string
+	}{
+		{name: "reset in a while", limit: 100, remaining: 0, reset: now.Add(30 * time.Second), wantRetryAfter: "30"},
+		{name: "partial second rounds up", limit: 10, remaining: 0, reset: now.Add(1500 * time.Millisecond), wantRetryAfter: "2"},
+		{name: "reset in the past", limit: 5, remaining: 2, reset: now.Add(-time.Minute), wantRetryAfter: "0"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := httptest.NewRecorder()
+			SendRateLimited(rec, tt.limit, tt.remaining, tt.reset)
+
+			if rec.Code != http.StatusTooManyRequests {
+				t.Errorf("status = %d, want %d", rec.Code, http.StatusTooManyRequests)
+			}
+
+			want := map[string]string{
+				"X-RateLimit-Limit":     strconv.Itoa(tt.limit),
+				"X-RateLimit-Remaining": strconv.Itoa(tt.remaining),
+				"X-RateLimit-Reset":     strconv.FormatInt(tt.reset.Unix(), 10),
+				"Retry-After":           tt.wantRetryAfter,
+				"Content-Type":          "application/json",
+			}
+			for name, value := range want {
+				if got := rec.Header().Get(name); got != value {
+					t.Errorf("%s = %q, want %q", name, got, value)
+				}
+			}
+		})
+	}
+}
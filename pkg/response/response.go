@@ -0,0 +1,83 @@
+package response
+
+import (
+	"bytes"
+	"net/http"
+	"sync/atomic"
+)
+
+// Response is the standard JSON envelope returned by the API.
+type Response struct {
+	Success bool        `json:"success"`
+	Message string      `json:"message,omitempty"`
+	Data    interface{} `json:"data,omitempty"`
+	Meta    *Meta       `json:"meta,omitempty"`
+	TraceID string      `json:"trace_id,omitempty"`
+}
+
+// Meta carries pagination details alongside the data.
+type Meta struct {
+	NextCursor string `json:"next_cursor,omitempty"`
+}
+
+// Send writes the standard envelope, marking it successful for non error status codes.
+// Error envelopes carry the trace ID when w was wrapped by WithTraceID.
+func Send(w http.ResponseWriter, statusCode int, message string, data interface{}) {
+	SendWithMeta(w, statusCode, message, data, nil)
+}
+
+// SendWithMeta writes the standard envelope including meta, e.g. the next page cursor.
+func SendWithMeta(w http.ResponseWriter, statusCode int, message string, data interface{}, meta *Meta) {
+	res := Response{
+		Success: statusCode < http.StatusBadRequest,
+		Message: message,
+		Data:    data,
+		Meta:    meta,
+	}
+
+	if !res.Success {
+		res.TraceID = traceIDOf(w)
+	}
+
+	SendJSON(w, statusCode, res)
+}
+
+// pretty switches SendJSON to indented output, see SetPretty.
+var pretty atomic.Bool
+
+// SetPretty enables indented JSON responses, handy in development. Output is
+// compact by default.
+func SetPretty(enabled bool) {
+	pretty.Store(enabled)
+}
+
+// SendJSON encodes payload as JSON and writes it with the given status code.
+// The payload is encoded to a buffer first so an encoding failure can still be
+// reported as a clean 500 before any header has been written.
+func SendJSON(w http.ResponseWriter, statusCode int, payload interface{}) {
+	var buf bytes.Buffer
+
+	enc := currentEncoder().NewEncoder(&buf)
+	if pretty.Load() {
+		enc.SetIndent("", "  ")
+	}
+
+	if err := enc.Encode(payload); err != nil {
+		http.Error(w, "Internal Server Error !", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(statusCode)
+
+	// a failed write means the client went away, there is nobody left to tell
+	_, _ = w.Write(buf.Bytes())
+}
+
+// SendBadRequest sends a 400 error envelope.
+func SendBadRequest(w http.ResponseWriter, message string) {
+	if message == "" {
+		message = http.StatusText(http.StatusBadRequest)
+	}
+	Send(w, http.StatusBadRequest, message, nil)
+}
@@ -0,0 +1,127 @@
+package response
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"syscall"
+	"testing"
+)
+
+// brokenPipeWriter fails every body write like a disconnected client would.
+type brokenPipeWriter struct {
+	*httptest.ResponseRecorder
+	headerWrites int
+}
+
+func (b *brokenPipeWriter) WriteHeader(status int) {
+	b.headerWrites++
+	b.ResponseRecorder.WriteHeader(status)
+}
+
+func (b *brokenPipeWriter) Write([]byte) (int, error) {
+	return 0, syscall.EPIPE
+}
+
+func TestSendJSON(t *testing.T) {
+	tests := []struct {
+		name        string
+		payload     interface{}
+		wantStatus  int
+		wantType    string
+		wantPayload bool
+	}{
+		{name: "encodes payload", payload: map[string]int{"count": 1}, wantStatus: http.StatusCreated, wantType: "application/json", wantPayload: true},
+		{name: "unmarshalable payload", payload: map[string]interface{}{"ch": make(chan int)}, wantStatus: http.StatusInternalServerError, wantType: "text/plain; charset=utf-8"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := httptest.NewRecorder()
+			SendJSON(rec, http.StatusCreated, tt.payload)
+
+			if rec.Code != tt.wantStatus {
+				t.Errorf("status = %d, want %d", rec.Code, tt.wantStatus)
+			}
+			if got := rec.Header().Get("Content-Type"); got != tt.wantType {
+				t.Errorf("Content-Type = %q, want %q", got, tt.wantType)
+			}
+			if tt.wantPayload && !json.Valid(rec.Body.Bytes()) {
+				t.Errorf("body is not valid JSON: %q", rec.Body.String())
+			}
+		})
+	}
+}
+
+func TestSendJSONClientGone(t *testing.T) {
+	w := &brokenPipeWriter{ResponseRecorder: httptest.NewRecorder()}
+
+	SendJSON(w, http.StatusOK, map[string]string{"status": "ok"})
+
+	if w.headerWrites != 1 {
+		t.Errorf("WriteHeader called %d times, want 1", w.headerWrites)
+	}
+	if w.Code != http.StatusOK {
+		t.Errorf("status = %d, want %d", w.Code, http.StatusOK)
+	}
+}
+
+func TestSendWithMeta(t *testing.T) {
+	tests := []struct {
+		name       string
+		meta       *Meta
+		wantMeta   bool
+		wantCursor string
+	}{
+		{name: "next cursor", meta: &Meta{NextCursor: "eyJpZCI6NDJ9"}, wantMeta: true, wantCursor: "eyJpZCI6NDJ9"},
+		{name: "no meta", meta: nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := httptest.NewRecorder()
+			SendWithMeta(rec, http.StatusOK, "", []int{1, 2}, tt.meta)
+
+			var body map[string]interface{}
+			if err := json.Unmarshal(rec.Body.Bytes(), &body); err != nil {
+				t.Fatal(err)
+			}
+
+			meta, ok := body["meta"].(map[string]interface{})
+			if ok != tt.wantMeta {
+				t.Fatalf("meta present = %v, want %v", ok, tt.wantMeta)
+			}
+			if ok && meta["next_cursor"] != tt.wantCursor {
+				t.Errorf("next_cursor = %v, want %q", meta["next_cursor"], tt.wantCursor)
+			}
+		})
+	}
+}
+
+func TestSendJSONPretty(t *testing.T) {
+	tests := []struct {
+		name   string
+		pretty bool
+		want   string
+	}{
+		{name: "compact by default", pretty: false, want: "{\"id\":1,\"tags\":[\"a\"]}\n"},
+		{name: "indented when enabled", pretty: true, want: "{\n  \"id\": 1,\n  \"tags\": [\n    \"a\"\n  ]\n}\n"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			SetPretty(tt.pretty)
+			defer SetPretty(false)
+
+			rec := httptest.NewRecorder()
+			SendJSON(rec, http.StatusOK, struct {
+				ID   int      `json:"id"`
+				Tags []string `json:"tags"`
+			}{ID: 1, Tags: []string{"a"}})
+
+			if got := rec.Body.String(); got != tt.want {
+				t.Errorf("body = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
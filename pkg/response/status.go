@@ -0,0 +1,83 @@
+package response
+
+import (
+	"net/http"
+	"strings"
+	"sync"
+)
+
+var (
+	statusHandlersMu sync.RWMutex
+	statusHandlers   = make(map[int]http.HandlerFunc)
+)
+
+// RegisterStatusHandler sets a custom renderer for code, e.g. a branded HTML
+// 404 page. It is used for clients asking for HTML, everyone else keeps
+// getting the JSON envelope.
+func RegisterStatusHandler(code int, fn func(w http.ResponseWriter, r *http.Request)) {
+	statusHandlersMu.Lock()
+	defer statusHandlersMu.Unlock()
+
+	statusHandlers[code] = fn
+}
+
+// WantsHTML reports whether the client prefers an HTML response over JSON.
+func WantsHTML(r *http.Request) bool {
+	accept := r.Header.Get("Accept")
+	html := strings.Index(accept, "text/html")
+	if html < 0 {
+		return false
+	}
+
+	// browsers list text/html first, API clients usually lead with application/json
+	json := strings.Index(accept, "application/json")
+	return json < 0 || html < json
+}
+
+// SendStatus renders the response for code through the registered status
+// handler when the client wants HTML, and as the JSON envelope otherwise.
+func SendStatus(w http.ResponseWriter, r *http.Request, code int) {
+	statusHandlersMu.RLock()
+	fn, ok := statusHandlers[code]
+	statusHandlersMu.RUnlock()
+
+	if ok && WantsHTML(r) {
+		fn(w, r)
+		return
+	}
+
+	Send(w, code, http.StatusText(code), nil)
+}
+
+// NotFound is an http.HandlerFunc rendering 404 through SendStatus.
+func NotFound(w http.ResponseWriter, r *http.Request) {
+	SendStatus(w, r, http.StatusNotFound)
+}
+
+// MethodNotAllowed is an http.HandlerFunc rendering 405 through SendStatus.
+func MethodNotAllowed(w http.ResponseWriter, r *http.Request) {
+	SendStatus(w, r, http.StatusMethodNotAllowed)
+}
+
+// InternalServerError is an http.HandlerFunc rendering 500 through SendStatus.
+func InternalServerError(w http.ResponseWriter, r *http.Request) {
+	SendStatus(w, r, http.StatusInternalServerError)
+}
+
+// ErrorHandlers groups the standard error pages.
+type ErrorHandlers struct {
+	NotFound            http.HandlerFunc
+	MethodNotAllowed    http.HandlerFunc
+	InternalServerError http.HandlerFunc
+}
+
+// DefaultErrorHandlers returns the standard 404/405/500 handlers emitting the
+// JSON envelope, or a registered status handler for HTML clients, so every
+// router wires the same error pages.
+func DefaultErrorHandlers() ErrorHandlers {
+	return ErrorHandlers{
+		NotFound:            NotFound,
+		MethodNotAllowed:    MethodNotAllowed,
+		InternalServerError: InternalServerError,
+	}
+}
@@ -0,0 +1,89 @@
+package response
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestSendStatusCustomHandler(t *testing.T) {
+	RegisterStatusHandler(http.StatusNotFound, func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/html")
+		w.WriteHeader(http.StatusNotFound)
+		w.Write([]byte("<h1>Lost?</h1>"))
+	})
+	t.Cleanup(func() {
+		statusHandlersMu.Lock()
+		delete(statusHandlers, http.StatusNotFound)
+		statusHandlersMu.Unlock()
+	})
+
+	tests := []struct {
+		name     string
+		accept   string
+		handler  http.HandlerFunc
+		wantType string
+		wantBody string
+	}{
+		{name: "browser gets the custom page", accept: "text/html,application/xhtml+xml,*/*;q=0.8", handler: NotFound, wantType: "text/html", wantBody: "Lost?"},
+		{name: "API client gets JSON", accept: "application/json, text/html", handler: NotFound, wantType: "application/json", wantBody: `"success":false`},
+		{name: "no Accept gets JSON", handler: NotFound, wantType: "application/json", wantBody: "Not Found"},
+		{name: "unregistered status gets JSON", accept: "text/html", handler: MethodNotAllowed, wantType: "application/json", wantBody: "Method Not Allowed"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/missing", nil)
+			if tt.accept != "" {
+				req.Header.Set("Accept", tt.accept)
+			}
+			rec := httptest.NewRecorder()
+			tt.handler(rec, req)
+
+			if got := rec.Header().Get("Content-Type"); got != tt.wantType {
+				t.Errorf("Content-Type = %q, want %q", got, tt.wantType)
+			}
+			if !strings.Contains(rec.Body.String(), tt.wantBody) {
+				t.Errorf("body = %q, want it to contain %q", rec.Body.String(), tt.wantBody)
+			}
+		})
+	}
+}
+
+func TestDefaultErrorHandlers(t *testing.T) {
+	handlers := DefaultErrorHandlers()
+
+	tests := []struct {
+		name       string
+		handler    http.HandlerFunc
+		wantStatus int
+	}{
+		{name: "not found", handler: handlers.NotFound, wantStatus: http.StatusNotFound},
+		{name: "method not allowed", handler: handlers.MethodNotAllowed, wantStatus: http.StatusMethodNotAllowed},
+		{name: "internal server error", handler: handlers.InternalServerError, wantStatus: http.StatusInternalServerError},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := httptest.NewRecorder()
+			tt.handler(rec, httptest.NewRequest(http.MethodGet, "/", nil))
+
+			if rec.Code != tt.wantStatus {
+				t.Errorf("status = %d, want %d", rec.Code, tt.wantStatus)
+			}
+			if got := rec.Header().Get("Content-Type"); got != "application/json" {
+				t.Errorf("Content-Type = %q, want application/json", got)
+			}
+
+			var res Response
+			if err := json.Unmarshal(rec.Body.Bytes(), &res); err != nil {
+				t.Fatal(err)
+			}
+			if res.Success || res.Message != http.StatusText(tt.wantStatus) {
+				t.Errorf("envelope = %+v, want a failed %q envelope", res, http.StatusText(tt.wantStatus))
+			}
+		})
+	}
+}
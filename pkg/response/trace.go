@@ -0,0 +1,49 @@
+package response
+
+import "net/http"
+
+// traceWriter carries the request's trace ID so error envelopes can include it.
+type traceWriter struct {
+	http.ResponseWriter
+	traceID string
+}
+
+func (t *traceWriter) TraceID() string {
+	return t.traceID
+}
+
+func (t *traceWriter) Flush() {
+	_ = t.FlushError()
+}
+
+// FlushError flushes like Flush but reports failures, see http.ResponseController.
+func (t *traceWriter) FlushError() error {
+	return http.NewResponseController(t.ResponseWriter).Flush()
+}
+
+// Unwrap lets http.ResponseController reach the underlying writer.
+func (t *traceWriter) Unwrap() http.ResponseWriter {
+	return t.ResponseWriter
+}
+
+// WithTraceID wraps w so error responses sent through it carry traceID.
+func WithTraceID(w http.ResponseWriter, traceID string) http.ResponseWriter {
+	return &traceWriter{ResponseWriter: w, traceID: traceID}
+}
+
+// traceIDOf returns the trace ID attached to w by WithTraceID, if any, looking
+// through writers wrapped by later middleware.
+func traceIDOf(w http.ResponseWriter) string {
+	for w != nil {
+		if tw, ok := w.(interface{ TraceID() string }); ok {
+			return tw.TraceID()
+		}
+
+		uw, ok := w.(interface{ Unwrap() http.ResponseWriter })
+		if !ok {
+			return ""
+		}
+		w = uw.Unwrap()
+	}
+	return ""
+}
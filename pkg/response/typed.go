@@ -0,0 +1,30 @@
+package response
+
+import "net/http"
+
+// TypedResponse is the standard envelope with a compile time checked payload type.
+type TypedResponse[T any] struct {
+	Success bool   `json:"success"`
+	Message string `json:"message,omitempty"`
+	Data    T      `json:"data"`
+	TraceID string `json:"trace_id,omitempty"`
+}
+
+// NewTypedResponse builds the envelope SendTyped writes.
+func NewTypedResponse[T any](statusCode int, message string, data T) TypedResponse[T] {
+	return TypedResponse[T]{
+		Success: statusCode < http.StatusBadRequest,
+		Message: message,
+		Data:    data,
+	}
+}
+
+// SendTyped is the typed counterpart of Send.
+func SendTyped[T any](w http.ResponseWriter, statusCode int, message string, data T) {
+	res := NewTypedResponse(statusCode, message, data)
+	if !res.Success {
+		res.TraceID = traceIDOf(w)
+	}
+
+	SendJSON(w, statusCode, res)
+}
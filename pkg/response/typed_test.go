@@ -0,0 +1,65 @@
+package response
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+)
+
+type typedUser struct {
+	ID   int    `json:"id"`
+	Name string `json:"name"`
+}
+
+func TestSendTypedStruct(t *testing.T) {
+	rec := httptest.NewRecorder()
+	SendTyped(rec, http.StatusOK, "found", typedUser{ID: 1, Name: "Ada"})
+
+	var got TypedResponse[typedUser]
+	if err := json.Unmarshal(rec.Body.Bytes(), &got); err != nil {
+		t.Fatal(err)
+	}
+
+	want := TypedResponse[typedUser]{Success: true, Message: "found", Data: typedUser{ID: 1, Name: "Ada"}}
+	if got != want {
+		t.Errorf("response = %+v, want %+v", got, want)
+	}
+}
+
+func TestSendTypedSlice(t *testing.T) {
+	tests := []struct {
+		name        string
+		status      int
+		data        []typedUser
+		wantSuccess bool
+	}{
+		{name: "list", status: http.StatusOK, data: []typedUser{{ID: 1, Name: "Ada"}, {ID: 2, Name: "Linus"}}, wantSuccess: true},
+		{name: "empty list", status: http.StatusOK, data: []typedUser{}, wantSuccess: true},
+		{name: "error status", status: http.StatusNotFound, data: nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := httptest.NewRecorder()
+			SendTyped(rec, tt.status, "", tt.data)
+
+			if rec.Code != tt.status {
+				t.Errorf("status = %d, want %d", rec.Code, tt.status)
+			}
+
+			var got TypedResponse[[]typedUser]
+			if err := json.Unmarshal(rec.Body.Bytes(), &got); err != nil {
+				t.Fatal(err)
+			}
+
+			if got.Success != tt.wantSuccess {
+				t.Errorf("success = %v, want %v", got.Success, tt.wantSuccess)
+			}
+			if !reflect.DeepEqual(got.Data, tt.data) {
+				t.Errorf("data = %+v, want %+v", got.Data, tt.data)
+			}
+		})
+	}
+}
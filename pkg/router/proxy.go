@@ -0,0 +1,92 @@
+package router
+
+import (
+	"log/slog"
+	"net/http"
+	"net/http/httputil"
+	"net/url"
+	"path"
+	"strings"
+
+	"github.com/himtar/go-boilerplate/pkg/response"
+	"github.com/himtar/go-boilerplate/pkg/trace"
+)
+
+// Proxy forwards every request under pattern to targetURL, e.g. with pattern
+// "/users/" and target "http://users:8080/v1" a request for /users/42 goes to
+// http://users:8080/v1/42. Trace headers are passed on and responses are
+// streamed back as they arrive.
+func (r *RouterMux) Proxy(pattern, targetURL string) error {
+	prefix := strings.TrimSuffix(pattern, "/")
+
+	proxy, err := NewProxy(targetURL, func(req *http.Request) string {
+		return strings.TrimPrefix(req.URL.Path, prefix)
+	})
+	if err != nil {
+		return err
+	}
+
+	r.Handle(pattern, proxy)
+
+	return nil
+}
+
+// NewProxy returns a reverse proxy to targetURL for routers mounting it
+// themselves. forwardPath gives the part of the request path appended to the
+// target's path, i.e. the path without the prefix the proxy is mounted at.
+func NewProxy(targetURL string, forwardPath func(req *http.Request) string) (http.Handler, error) {
+	target, err := url.Parse(targetURL)
+	if err != nil {
+		return nil, err
+	}
+
+	proxy := &httputil.ReverseProxy{
+		Director: func(req *http.Request) {
+			rest := forwardPath(req)
+
+			req.URL.Scheme = target.Scheme
+			req.URL.Host = target.Host
+			req.URL.Path = joinProxyPath(target.Path, rest)
+			req.URL.RawPath = ""
+			req.Host = target.Host
+
+			if target.RawQuery != "" && req.URL.RawQuery != "" {
+				req.URL.RawQuery = target.RawQuery + "&" + req.URL.RawQuery
+			} else if target.RawQuery != "" {
+				req.URL.RawQuery = target.RawQuery
+			}
+
+			// incoming headers are copied as is, make sure the trace survives when
+			// the id was generated here rather than received
+			if traceID := trace.ID(req.Context()); traceID != "" {
+				req.Header.Set(trace.Header, traceID)
+			}
+		},
+		// flush right away so streamed responses aren't held back
+		FlushInterval: -1,
+		ErrorHandler: func(w http.ResponseWriter, req *http.Request, err error) {
+			slog.ErrorContext(req.Context(), "proxy request failed",
+				slog.String("target", target.String()),
+				slog.String("path", req.URL.Path),
+				slog.String("error", err.Error()),
+			)
+			response.Send(w, http.StatusBadGateway, http.StatusText(http.StatusBadGateway), nil)
+		},
+	}
+
+	return proxy, nil
+}
+
+// joinProxyPath appends the forwarded part of the path to the target's base path.
+func joinProxyPath(base, rest string) string {
+	if rest == "" {
+		rest = "/"
+	}
+
+	joined := path.Join("/", base, rest)
+	if strings.HasSuffix(rest, "/") && !strings.HasSuffix(joined, "/") {
+		joined += "/"
+	}
+
+	return joined
+}
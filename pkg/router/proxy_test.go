@@ -0,0 +1,142 @@
+package router
+
+import (
+	"bufio"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/himtar/go-boilerplate/pkg/trace"
+)
+
+func TestProxyForwardsAndRelays(t *testing.T) {
+	var got *http.Request
+	upstream := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		got = r
+		w.Header().Set("X-Upstream", "users")
+		w.WriteHeader(http.StatusCreated)
+		io.WriteString(w, "relayed")
+	}))
+	defer upstream.Close()
+
+	tests := []struct {
+		name        string
+		target      string
+		path        string
+		headerTrace string
+		ctxTrace    string
+		wantPath    string
+		wantQuery   string
+	}{
+		{name: "strips the prefix", target: upstream.URL + "/v1", path: "/users/42?full=1", wantPath: "/v1/42", wantQuery: "full=1"},
+		{name: "prefix root", target: upstream.URL + "/v1", path: "/users/", wantPath: "/v1/"},
+		{name: "target without base path", target: upstream.URL, path: "/users/42/orders", wantPath: "/42/orders"},
+		{name: "target query kept", target: upstream.URL + "/v1?key=abc", path: "/users/42?full=1", wantPath: "/v1/42", wantQuery: "key=abc&full=1"},
+		{name: "incoming trace kept", target: upstream.URL, path: "/users/42", headerTrace: "trace-123", wantPath: "/42"},
+		{name: "trace from context forwarded", target: upstream.URL, path: "/users/42", ctxTrace: "trace-456", wantPath: "/42"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mux := NewRouterMux()
+			if err := mux.Proxy("/users/", tt.target); err != nil {
+				t.Fatal(err)
+			}
+
+			req := httptest.NewRequest(http.MethodGet, tt.path, nil)
+			if tt.headerTrace != "" {
+				req.Header.Set(trace.Header, tt.headerTrace)
+			}
+			if tt.ctxTrace != "" {
+				req = req.WithContext(trace.NewContext(req.Context(), tt.ctxTrace))
+			}
+			rec := httptest.NewRecorder()
+			mux.ServeHTTP(rec, req)
+
+			if rec.Code != http.StatusCreated || rec.Body.String() != "relayed" || rec.Header().Get("X-Upstream") != "users" {
+				t.Errorf("response = %d %q %v, want the upstream response", rec.Code, rec.Body.String(), rec.Header())
+			}
+
+			if got.URL.Path != tt.wantPath {
+				t.Errorf("upstream path = %q, want %q", got.URL.Path, tt.wantPath)
+			}
+			if got.URL.RawQuery != tt.wantQuery {
+				t.Errorf("upstream query = %q, want %q", got.URL.RawQuery, tt.wantQuery)
+			}
+
+			wantTrace := tt.headerTrace
+			if tt.ctxTrace != "" {
+				wantTrace = tt.ctxTrace
+			}
+			if traceID := got.Header.Get(trace.Header); traceID != wantTrace {
+				t.Errorf("upstream trace ID = %q, want %q", traceID, wantTrace)
+			}
+		})
+	}
+}
+
+func TestProxyStreamsResponse(t *testing.T) {
+	release := make(chan struct{})
+	upstream := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		io.WriteString(w, "first\n")
+		w.(http.Flusher).Flush()
+		<-release
+		io.WriteString(w, "second\n")
+	}))
+	defer upstream.Close()
+	defer close(release)
+
+	mux := NewRouterMux()
+	if err := mux.Proxy("/events/", upstream.URL); err != nil {
+		t.Fatal(err)
+	}
+	gateway := httptest.NewServer(mux)
+	defer gateway.Close()
+
+	res, err := http.Get(gateway.URL + "/events/")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer res.Body.Close()
+
+	line := make(chan string, 1)
+	go func() {
+		first, _ := bufio.NewReader(res.Body).ReadString('\n')
+		line <- first
+	}()
+
+	select {
+	case first := <-line:
+		if first != "first\n" {
+			t.Errorf("first line = %q, want %q", first, "first\n")
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("first chunk held back until the upstream finished")
+	}
+}
+
+func TestProxyUpstreamDown(t *testing.T) {
+	upstream := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	target := upstream.URL
+	upstream.Close()
+
+	mux := NewRouterMux()
+	if err := mux.Proxy("/users/", target); err != nil {
+		t.Fatal(err)
+	}
+
+	rec := httptest.NewRecorder()
+	mux.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/users/42", nil))
+
+	if rec.Code != http.StatusBadGateway {
+		t.Errorf("status = %d, want %d", rec.Code, http.StatusBadGateway)
+	}
+}
+
+func TestProxyInvalidTarget(t *testing.T) {
+	if err := NewRouterMux().Proxy("/users/", "http://[::1"); err == nil {
+		t.Error("invalid target URL accepted")
+	}
+}
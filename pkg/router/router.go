@@ -3,8 +3,8 @@ package router
 import (
 	"net/http"
 
-	"github.com/himtar/go-boilerplate/pkg/errors"
 	"github.com/himtar/go-boilerplate/pkg/helpers"
+	"github.com/himtar/go-boilerplate/pkg/response"
 )
 
 type RouterMux struct {
@@ -59,9 +59,7 @@ func (r *RouterMux) AddCustomDeleteHandler(urlPath string, handler http.HandlerF
 }
 
 func (r *RouterMux) AddUnsupportedMethodHandler(urlPath string) {
-	r.HandleFunc(urlPath, func(w http.ResponseWriter, r *http.Request) {
-		errors.MethodNotAllowed(w)
-	})
+	r.HandleFunc(urlPath, response.DefaultErrorHandlers().MethodNotAllowed)
 }
 
 // AddCustomHandler registers a custom handler for the specified method and urlPath.
@@ -0,0 +1,97 @@
+package router
+
+import (
+	"bytes"
+	"mime"
+	"net/http"
+	"path"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// defaultRobots lets every crawler in.
+const defaultRobots = "User-agent: *\nDisallow:\n"
+
+// StaticAsset serves content with contentType, cacheable for a day. HEAD
+// and conditional requests are handled.
+func StaticAsset(content []byte, contentType string) http.HandlerFunc {
+	modified := time.Now()
+
+	return func(w http.ResponseWriter, req *http.Request) {
+		w.Header().Set("Content-Type", contentType)
+		w.Header().Set("Cache-Control", "public, max-age="+strconv.Itoa(24*60*60))
+		http.ServeContent(w, req, "", modified, bytes.NewReader(content))
+	}
+}
+
+// FaviconHandler serves icon as /favicon.ico. Without an icon it answers 204,
+// so browsers stop asking without filling the logs with 404s.
+func FaviconHandler(icon []byte) http.HandlerFunc {
+	if len(icon) == 0 {
+		return func(w http.ResponseWriter, req *http.Request) {
+			w.WriteHeader(http.StatusNoContent)
+		}
+	}
+	return StaticAsset(icon, "image/x-icon")
+}
+
+// RobotsHandler serves robots as /robots.txt, allowing every crawler when empty.
+func RobotsHandler(robots string) http.HandlerFunc {
+	if robots == "" {
+		robots = defaultRobots
+	}
+	return StaticAsset([]byte(robots), "text/plain; charset=utf-8")
+}
+
+// Static serves the files under dir. When the client accepts gzip and a
+// pre-compressed ".gz" sibling exists, e.g. app.js.gz next to app.js, that one
+// is sent with Content-Encoding gzip instead, sparing the compression on
+// every request. Mount it with http.StripPrefix when served under a prefix.
+func Static(dir string) http.Handler {
+	root := http.Dir(dir)
+	files := http.FileServer(root)
+
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		w.Header().Add("Vary", "Accept-Encoding")
+
+		name := path.Clean("/" + req.URL.Path)
+		if strings.Contains(req.Header.Get("Accept-Encoding"), "gzip") && !strings.HasSuffix(name, "/") {
+			if serveGzipped(w, req, root, name) {
+				return
+			}
+		}
+
+		files.ServeHTTP(w, req)
+	})
+}
+
+// serveGzipped sends name's .gz sibling if there is one, reporting whether it did.
+func serveGzipped(w http.ResponseWriter, req *http.Request, root http.FileSystem, name string) bool {
+	gz, err := root.Open(name + ".gz")
+	if err != nil {
+		return false
+	}
+	defer gz.Close()
+
+	info, err := gz.Stat()
+	if err != nil || info.IsDir() {
+		return false
+	}
+
+	contentType := mime.TypeByExtension(path.Ext(name))
+	if contentType == "" {
+		contentType = "application/octet-stream"
+	}
+
+	w.Header().Set("Content-Type", contentType)
+	w.Header().Set("Content-Encoding", "gzip")
+	http.ServeContent(w, req, name, info.ModTime(), gz)
+	return true
+}
+
+// Static registers a handler serving the files under dir at urlPath, which
+// should end with a slash to cover everything below it.
+func (r *RouterMux) Static(urlPath, dir string) {
+	r.Handle(urlPath, http.StripPrefix(strings.TrimSuffix(urlPath, "/"), Static(dir)))
+}
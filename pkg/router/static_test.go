@@ -0,0 +1,153 @@
+package router
+
+import (
+	"bytes"
+	"compress/gzip"
+	"io"
+	"mime"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestStaticAssetHandlers(t *testing.T) {
+	mux := NewRouterMux()
+	mux.HandleFunc("/favicon.ico", FaviconHandler([]byte("icon")))
+	mux.HandleFunc("/robots.txt", RobotsHandler(""))
+	mux.HandleFunc("/empty.ico", FaviconHandler(nil))
+
+	tests := []struct {
+		name            string
+		method          string
+		path            string
+		wantStatus      int
+		wantContentType string
+		wantBody        string
+	}{
+		{name: "asset", method: http.MethodGet, path: "/favicon.ico", wantStatus: http.StatusOK, wantContentType: "image/x-icon", wantBody: "icon"},
+		{name: "default robots", method: http.MethodGet, path: "/robots.txt", wantStatus: http.StatusOK, wantContentType: "text/plain; charset=utf-8", wantBody: defaultRobots},
+		{name: "no favicon", method: http.MethodGet, path: "/empty.ico", wantStatus: http.StatusNoContent},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := httptest.NewRecorder()
+			mux.ServeHTTP(rec, httptest.NewRequest(tt.method, tt.path, nil))
+
+			if rec.Code != tt.wantStatus {
+				t.Fatalf("status = %d, want %d", rec.Code, tt.wantStatus)
+			}
+			if tt.wantContentType != "" && rec.Header().Get("Content-Type") != tt.wantContentType {
+				t.Errorf("Content-Type = %q, want %q", rec.Header().Get("Content-Type"), tt.wantContentType)
+			}
+			if rec.Body.String() != tt.wantBody {
+				t.Errorf("body = %q, want %q", rec.Body.String(), tt.wantBody)
+			}
+		})
+	}
+}
+
+func TestStaticAssetConditionalRequest(t *testing.T) {
+	handler := StaticAsset([]byte("User-agent: *\n"), "text/plain")
+
+	rec := httptest.NewRecorder()
+	handler(rec, httptest.NewRequest(http.MethodHead, "/robots.txt", nil))
+
+	if rec.Code != http.StatusOK || rec.Body.Len() != 0 {
+		t.Errorf("HEAD = %d with %d bytes, want %d without a body", rec.Code, rec.Body.Len(), http.StatusOK)
+	}
+
+	lastModified := rec.Header().Get("Last-Modified")
+	if lastModified == "" {
+		t.Fatal("no Last-Modified header")
+	}
+	if rec.Header().Get("Cache-Control") == "" {
+		t.Error("no Cache-Control header")
+	}
+
+	req := httptest.NewRequest(http.MethodGet, "/robots.txt", nil)
+	req.Header.Set("If-Modified-Since", lastModified)
+	rec = httptest.NewRecorder()
+	handler(rec, req)
+
+	if rec.Code != http.StatusNotModified {
+		t.Errorf("conditional status = %d, want %d", rec.Code, http.StatusNotModified)
+	}
+}
+
+func TestStaticServesGzippedSiblings(t *testing.T) {
+	dir := t.TempDir()
+	write := func(name string, data []byte) {
+		if err := os.WriteFile(filepath.Join(dir, name), data, 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	var gz bytes.Buffer
+	zw := gzip.NewWriter(&gz)
+	zw.Write([]byte("console.log('gz')"))
+	zw.Close()
+
+	write("app.js", []byte("console.log('plain')"))
+	write("app.js.gz", gz.Bytes())
+	write("style.css", []byte("body{}"))
+
+	mux := NewRouterMux()
+	mux.Static("/assets/", dir)
+
+	tests := []struct {
+		name         string
+		path         string
+		acceptGzip   bool
+		wantEncoding string
+		wantBody     string
+	}{
+		{name: "gz sibling, gzip accepted", path: "/assets/app.js", acceptGzip: true, wantEncoding: "gzip", wantBody: "console.log('gz')"},
+		{name: "gz sibling, gzip not accepted", path: "/assets/app.js", wantBody: "console.log('plain')"},
+		{name: "no gz sibling", path: "/assets/style.css", acceptGzip: true, wantBody: "body{}"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, tt.path, nil)
+			if tt.acceptGzip {
+				req.Header.Set("Accept-Encoding", "gzip")
+			}
+			rec := httptest.NewRecorder()
+			mux.ServeHTTP(rec, req)
+
+			if rec.Code != http.StatusOK {
+				t.Fatalf("status = %d, want %d", rec.Code, http.StatusOK)
+			}
+			if got := rec.Header().Get("Content-Encoding"); got != tt.wantEncoding {
+				t.Errorf("Content-Encoding = %q, want %q", got, tt.wantEncoding)
+			}
+			if rec.Header().Get("Vary") != "Accept-Encoding" {
+				t.Errorf("Vary = %q, want Accept-Encoding", rec.Header().Get("Vary"))
+			}
+
+			var body io.Reader = rec.Body
+			if tt.wantEncoding == "gzip" {
+				zr, err := gzip.NewReader(rec.Body)
+				if err != nil {
+					t.Fatal(err)
+				}
+				body = zr
+
+				if ct := rec.Header().Get("Content-Type"); ct != mime.TypeByExtension(".js") {
+					t.Errorf("Content-Type = %q, want the type of app.js", ct)
+				}
+			}
+
+			got, err := io.ReadAll(body)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if string(got) != tt.wantBody {
+				t.Errorf("body = %q, want %q", got, tt.wantBody)
+			}
+		})
+	}
+}
@@ -0,0 +1,19 @@
+package trace
+
+import "context"
+
+// Header carries the trace ID in both directions, on inbound and outbound requests.
+const Header = "X-Trace-ID"
+
+type contextKey struct{}
+
+// NewContext returns a copy of ctx carrying traceID.
+func NewContext(ctx context.Context, traceID string) context.Context {
+	return context.WithValue(ctx, contextKey{}, traceID)
+}
+
+// ID returns the trace ID stored by NewContext, "" if absent.
+func ID(ctx context.Context) string {
+	traceID, _ := ctx.Value(contextKey{}).(string)
+	return traceID
+}
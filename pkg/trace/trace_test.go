@@ -0,0 +1,26 @@
+package trace
+
+import (
+	"context"
+	"testing"
+)
+
+func TestID(t *testing.T) {
+	tests := []struct {
+		name string
+		ctx  context.Context
+		want string
+	}{
+		{name: "stored", ctx: NewContext(context.Background(), "trace-1"), want: "trace-1"},
+		{name: "absent", ctx: context.Background()},
+		{name: "innermost wins", ctx: NewContext(NewContext(context.Background(), "outer"), "inner"), want: "inner"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := ID(tt.ctx); got != tt.want {
+				t.Errorf("ID() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}